@@ -5,6 +5,7 @@ import (
 	"github.com/adene-develop/adene-goeth/contract"
 	"github.com/adene-develop/adene-goeth/eth"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
 )
 
 type ADENE interface {
@@ -14,12 +15,22 @@ type ADENE interface {
 	ReflectionFromToken(ctx context.Context, tAmount int64, deductTransferFee bool) (int64, error)
 	TokenFromReflection(ctx context.Context, rAmount int64) (int64, error)
 	IsExcludedFromFee(ctx context.Context, account common.Address) (bool, error)
+
+	// ExcludeFromFee excludes `account` from transfer fees. Can only be called by the owner.
+	ExcludeFromFee(ctx context.Context, account common.Address) (common.Hash, error)
+
+	// IncludeInFee includes `account` in transfer fees again. Can only be called by the owner.
+	IncludeInFee(ctx context.Context, account common.Address) (common.Hash, error)
+
+	// SetSwapAndLiquifyEnabled enables or disables swap and liquify. Can only be called by the owner.
+	SetSwapAndLiquifyEnabled(ctx context.Context, enabled bool) (common.Hash, error)
 }
 
-func NewADENEContract(client *eth.Client, address common.Address) ADENE {
+func NewADENEContract(backend eth.Backend, address common.Address, opts ...eth.Option) ADENE {
+	client := eth.AsClient(backend, opts...)
 	return &ADENEContract{
-		ERC20:   contract.NewERC20(client, address),
-		Ownable: contract.NewOwnable(client, address),
+		ERC20:   contract.NewERC20(client.Backend(), address),
+		Ownable: contract.NewOwnable(client.Backend(), address),
 		client:  client,
 		address: address,
 	}
@@ -46,3 +57,36 @@ func (A *ADENEContract) IsExcludedFromFee(ctx context.Context, account common.Ad
 	//TODO implement me
 	panic("implement me")
 }
+
+func (A *ADENEContract) ExcludeFromFee(ctx context.Context, account common.Address) (common.Hash, error) {
+	ctx, span := A.client.StartSpan(ctx, "ADENEContract", "ExcludeFromFee", A.address)
+	defer span.End()
+
+	txHash, err := A.client.SendContractTransaction(ctx, ABI, A.address, "excludeFromFee", account)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "ADENEContract send `excludeFromFee` transaction error")
+	}
+	return txHash, nil
+}
+
+func (A *ADENEContract) IncludeInFee(ctx context.Context, account common.Address) (common.Hash, error) {
+	ctx, span := A.client.StartSpan(ctx, "ADENEContract", "IncludeInFee", A.address)
+	defer span.End()
+
+	txHash, err := A.client.SendContractTransaction(ctx, ABI, A.address, "includeInFee", account)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "ADENEContract send `includeInFee` transaction error")
+	}
+	return txHash, nil
+}
+
+func (A *ADENEContract) SetSwapAndLiquifyEnabled(ctx context.Context, enabled bool) (common.Hash, error) {
+	ctx, span := A.client.StartSpan(ctx, "ADENEContract", "SetSwapAndLiquifyEnabled", A.address)
+	defer span.End()
+
+	txHash, err := A.client.SendContractTransaction(ctx, ABI, A.address, "setSwapAndLiquifyEnabled", enabled)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "ADENEContract send `setSwapAndLiquifyEnabled` transaction error")
+	}
+	return txHash, nil
+}
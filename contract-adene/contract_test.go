@@ -0,0 +1,98 @@
+package adene_test
+
+import (
+	"github.com/adene-develop/adene-goeth/contract-adene"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"testing"
+)
+
+var (
+	adeneAddress = common.HexToAddress("0x00000000000000000000000000000000000000ad")
+	alice        = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	bob          = common.HexToAddress("0x00000000000000000000000000000000000000b0")
+)
+
+type events struct {
+	calls []string
+}
+
+func (e *events) Transfer(from, to common.Address, value *big.Int) {
+	e.calls = append(e.calls, "Transfer "+from.Hex()+" "+to.Hex()+" "+value.String())
+}
+
+func (e *events) Approval(owner, spender common.Address, value *big.Int) {
+	e.calls = append(e.calls, "Approval "+owner.Hex()+" "+spender.Hex()+" "+value.String())
+}
+
+func (e *events) OwnershipTransferred(previousOwner common.Address, newOwner common.Address) {
+	e.calls = append(e.calls, "OwnershipTransferred "+previousOwner.Hex()+" "+newOwner.Hex())
+}
+
+func (e *events) MinTokensBeforeSwapUpdated(minTokensBeforeSwap int64) {
+	e.calls = append(e.calls, "MinTokensBeforeSwapUpdated")
+}
+
+func (e *events) SwapAndLiquifyEnabledUpdated(enabled bool) {
+	e.calls = append(e.calls, "SwapAndLiquifyEnabledUpdated")
+}
+
+func (e *events) SwapAndLiquify(tokensSwapped, ethReceived, tokensIntoLiquidity int64) {
+	e.calls = append(e.calls, "SwapAndLiquify")
+}
+
+func TestERC20Views(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	contract := adene.NewADENEContract(client.Backend(), adeneAddress)
+	node.OnCall(adeneAddress, adene.ABI, "symbol", nil, "ADENE")
+	node.OnCall(adeneAddress, adene.ABI, "balanceOf", []interface{}{alice}, big.NewInt(250))
+	node.OnCall(adeneAddress, adene.ABI, "owner", nil, bob)
+	ctx := ethtest.Context(t)
+
+	symbol, err := contract.Symbol(ctx)
+	if err != nil || symbol != "ADENE" {
+		t.Fatalf("Symbol = %q, %v; want ADENE", symbol, err)
+	}
+	balance, err := contract.BalanceOf(ctx, alice)
+	if err != nil || balance.Int64() != 250 {
+		t.Fatalf("BalanceOf = %v, %v; want 250", balance, err)
+	}
+	owner, err := contract.Owner(ctx)
+	if err != nil || owner != bob {
+		t.Fatalf("Owner = %s, %v; want %s", owner.Hex(), err, bob.Hex())
+	}
+}
+
+func TestParseEvents(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	node.AddEvent(1, adeneAddress, adene.ABI, "Transfer", alice, bob, big.NewInt(10))
+	node.AddEvent(2, adeneAddress, adene.ABI, "OwnershipTransferred", alice, bob)
+
+	changes, err := client.EthGetLogs(ethtest.Context(t), eth.FilterQuery{
+		FromBlock: eth.AtBlock(0),
+		ToBlock:   eth.AtBlock(2),
+		Addresses: []common.Address{adeneAddress},
+	})
+	if err != nil {
+		t.Fatalf("EthGetLogs: %v", err)
+	}
+
+	got := &events{}
+	if err = adene.ParseEvents(changes, got, nil); err != nil {
+		t.Fatalf("ParseEvents: %v", err)
+	}
+	want := []string{
+		"Transfer " + alice.Hex() + " " + bob.Hex() + " 10",
+		"OwnershipTransferred " + alice.Hex() + " " + bob.Hex(),
+	}
+	if len(got.calls) != len(want) {
+		t.Fatalf("got events %v, want %v", got.calls, want)
+	}
+	for i := range want {
+		if got.calls[i] != want[i] {
+			t.Fatalf("event %d = %q, want %q", i, got.calls[i], want[i])
+		}
+	}
+}
@@ -3,6 +3,7 @@ package adene
 import (
 	"github.com/adene-develop/adene-goeth/contract"
 	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/pkg/errors"
 )
 
 type Events interface {
@@ -14,14 +15,34 @@ type Events interface {
 	SwapAndLiquify(tokensSwapped, ethReceived, tokensIntoLiquidity int64)
 }
 
-func ParseEvents(filterChanges []*eth.FilterChange, events Events) error {
-	if err := contract.ParseERC20Events(filterChanges, events); err != nil {
-		return err
+// ParseEvents calls the callback of `events` for each ADENE event in `filterChanges`, in the order of the logs.
+// A log removed from the chain by a reorg calls the callback of `rollback` instead, to undo the event.
+// If `rollback` is nil and a removed event is found, eth.ErrRemovedEventNotHandled is returned before any callback is called.
+func ParseEvents(filterChanges []*eth.FilterChange, events Events, rollback Events) error {
+	if rollback == nil {
+		err := eth.CheckRemovedEvents(filterChanges, ABI.Events["Transfer"], ABI.Events["Approval"], ABI.Events["OwnershipTransferred"])
+		if err != nil {
+			return errors.Wrap(err, "adene parse events error")
+		}
 	}
-	if err := contract.ParseOwnableEvents(filterChanges, events); err != nil {
-		return err
+	for _, change := range filterChanges {
+		target := events
+		if change.Removed {
+			target = rollback
+		}
+		if err := parseEvent(change, target); err != nil {
+			return errors.Wrap(err, "adene parse events error")
+		}
 	}
+	return nil
+}
 
+// parseEvent calls the callback of `events` for `change` if it is an ADENE event
+func parseEvent(change *eth.FilterChange, events Events) error {
+	if ok, err := contract.ParseERC20Event(change, events); ok {
+		return err
+	}
 	// TODO implement other events
-	return nil
+	_, err := contract.ParseOwnableEvent(change, events)
+	return err
 }
@@ -0,0 +1,17 @@
+package icon721
+
+import (
+	"github.com/adene-develop/adene-goeth/contract"
+	"github.com/adene-develop/adene-goeth/eth"
+)
+
+// RegisterCachePolicies sets the cache policies of the ICON721 view functions on top of {contract.RegisterCachePolicies}:
+// {tokenURI} is cached for one block, because the contract emits no event when the base URI changes
+// and another account may call {setBaseURI}. It is also dropped when the client sends a {setBaseURI} or {burn} transaction.
+func RegisterCachePolicies(cache *eth.ViewCache) {
+	contract.RegisterCachePolicies(cache)
+
+	cache.SetPolicy(ABI, "tokenURI", eth.CachePerBlock)
+	cache.InvalidateOnTransaction(ABI, "setBaseURI", "tokenURI")
+	cache.InvalidateOnTransaction(ABI, "burn", "tokenURI")
+}
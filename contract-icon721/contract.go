@@ -6,6 +6,8 @@ import (
 	"github.com/adene-develop/adene-goeth/eth"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
+	"math"
+	"math/big"
 )
 
 const ContractName = "ICON721"
@@ -18,11 +20,26 @@ type ICON721 interface {
 	// `allocated` is the total number of ICON721 that `user` can be mint
 	// `remainingAllocation` is the remaining number of ICON721 that `user` can be mint
 	InfoWallet(ctx context.Context, user common.Address) (allocated int64, remainingAllocation int64, err error)
+
+	// MintTo mints `amount` of ICON721 to `to`
+	MintTo(ctx context.Context, to common.Address, amount int) (common.Hash, error)
+
+	// SetAllocations sets the number of ICON721 that each of `users` can be mint
+	// `users` and `allocations` must have the same length
+	SetAllocations(ctx context.Context, users []common.Address, allocations []int64) (common.Hash, error)
+
+	// SetBaseURI sets the base URI used to build the token URI of every ICON721
+	SetBaseURI(ctx context.Context, baseURI string) (common.Hash, error)
+
+	// Burn destroys the `tokenId` token
+	Burn(ctx context.Context, tokenID int64) (common.Hash, error)
 }
 
-func NewIcon721Contract(client *eth.Client, address common.Address) ICON721 {
+func NewIcon721Contract(backend eth.Backend, address common.Address, opts ...eth.Option) ICON721 {
+	client := eth.AsClient(backend, opts...)
 	return &icon721{
-		ERC721Enumerable: contract.NewERC721Enumerable(client, address),
+		ERC721Enumerable: contract.NewERC721Enumerable(client.Backend(), address),
+		Ownable:          contract.NewOwnable(client.Backend(), address),
 		address:          address,
 		client:           client,
 	}
@@ -36,15 +53,76 @@ type icon721 struct {
 }
 
 func (i *icon721) InfoWallet(ctx context.Context, user common.Address) (allocated int64, remainingAllocation int64, err error) {
-	var result struct {
-		Allocated           uint16
-		RemainingAllocation uint16
-	}
+	ctx, span := i.client.StartSpan(ctx, "icon721", "InfoWallet", i.address)
+	defer span.End()
+
+	// infoWallet outputs are unnamed so they cannot be unpacked into a struct
+	var result [2]uint16
 
 	err = i.client.CallContractViewFunction(ctx, ABI, i.address, &result, "infoWallet", user)
 	if err != nil {
 		return 0, 0, errors.Wrap(err, "icon721 call view `infoWallet` error")
 	}
 
-	return int64(result.Allocated), int64(result.RemainingAllocation), nil
+	return int64(result[0]), int64(result[1]), nil
+}
+
+func (i *icon721) MintTo(ctx context.Context, to common.Address, amount int) (common.Hash, error) {
+	ctx, span := i.client.StartSpan(ctx, "icon721", "MintTo", i.address)
+	defer span.End()
+
+	if amount <= 0 || amount > math.MaxUint16 {
+		return common.Hash{}, eth.RecordSpanError(ctx, errors.Errorf("icon721 invalid mint amount %d", amount))
+	}
+
+	txHash, err := i.client.SendContractTransaction(ctx, ABI, i.address, "mintTo", to, uint16(amount))
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "icon721 send `mintTo` transaction error")
+	}
+	return txHash, nil
+}
+
+func (i *icon721) SetAllocations(ctx context.Context, users []common.Address, allocations []int64) (common.Hash, error) {
+	ctx, span := i.client.StartSpan(ctx, "icon721", "SetAllocations", i.address)
+	defer span.End()
+
+	if len(users) != len(allocations) {
+		return common.Hash{}, eth.RecordSpanError(ctx, errors.New("icon721 users and allocations length mismatch"))
+	}
+
+	args := make([]uint16, len(allocations))
+	for j, allocation := range allocations {
+		if allocation < 0 || allocation > math.MaxUint16 {
+			return common.Hash{}, eth.RecordSpanError(ctx, errors.Errorf("icon721 invalid allocation %d of user %s", allocation, users[j].Hex()))
+		}
+		args[j] = uint16(allocation)
+	}
+
+	txHash, err := i.client.SendContractTransaction(ctx, ABI, i.address, "setAllocations", users, args)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "icon721 send `setAllocations` transaction error")
+	}
+	return txHash, nil
+}
+
+func (i *icon721) SetBaseURI(ctx context.Context, baseURI string) (common.Hash, error) {
+	ctx, span := i.client.StartSpan(ctx, "icon721", "SetBaseURI", i.address)
+	defer span.End()
+
+	txHash, err := i.client.SendContractTransaction(ctx, ABI, i.address, "setBaseURI", baseURI)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "icon721 send `setBaseURI` transaction error")
+	}
+	return txHash, nil
+}
+
+func (i *icon721) Burn(ctx context.Context, tokenID int64) (common.Hash, error) {
+	ctx, span := i.client.StartSpan(ctx, "icon721", "Burn", i.address)
+	defer span.End()
+
+	txHash, err := i.client.SendContractTransaction(ctx, ABI, i.address, "burn", big.NewInt(tokenID))
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "icon721 send `burn` transaction error")
+	}
+	return txHash, nil
 }
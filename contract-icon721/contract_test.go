@@ -0,0 +1,161 @@
+package icon721_test
+
+import (
+	stderrors "errors"
+	"github.com/adene-develop/adene-goeth/contract-icon721"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+var (
+	icon721Address = common.HexToAddress("0x00000000000000000000000000000000000000c7")
+	alice          = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	bob            = common.HexToAddress("0x00000000000000000000000000000000000000b0")
+)
+
+type events struct {
+	calls []string
+}
+
+func (e *events) Transfer(from common.Address, to common.Address, tokenID int64) {
+	e.calls = append(e.calls, "Transfer "+from.Hex()+" "+to.Hex()+" "+big.NewInt(tokenID).String())
+}
+
+func (e *events) Approval(owner common.Address, approved common.Address, tokenID int64) {
+	e.calls = append(e.calls, "Approval "+owner.Hex()+" "+approved.Hex()+" "+big.NewInt(tokenID).String())
+}
+
+func (e *events) ApprovalForAll(owner common.Address, operator common.Address, approved bool) {
+	e.calls = append(e.calls, "ApprovalForAll")
+}
+
+func (e *events) OwnershipTransferred(previousOwner common.Address, newOwner common.Address) {
+	e.calls = append(e.calls, "OwnershipTransferred "+previousOwner.Hex()+" "+newOwner.Hex())
+}
+
+func TestInfoWallet(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	contract := icon721.NewIcon721Contract(client.Backend(), icon721Address)
+	node.OnCall(icon721Address, icon721.ABI, "infoWallet", []interface{}{alice}, uint16(5), uint16(3))
+
+	allocated, remaining, err := contract.InfoWallet(ethtest.Context(t), alice)
+	if err != nil {
+		t.Fatalf("InfoWallet: %v", err)
+	}
+	if allocated != 5 || remaining != 3 {
+		t.Fatalf("InfoWallet = %d, %d; want 5, 3", allocated, remaining)
+	}
+}
+
+func TestParseEvents(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	node.AddEvent(1, icon721Address, icon721.ABI, "OwnershipTransferred", common.Address{}, alice)
+	node.AddEvent(2, icon721Address, icon721.ABI, "Transfer", common.Address{}, bob, big.NewInt(42))
+
+	changes, err := client.EthGetLogs(ethtest.Context(t), eth.FilterQuery{
+		FromBlock: eth.AtBlock(0),
+		ToBlock:   eth.AtBlock(2),
+		Addresses: []common.Address{icon721Address},
+	})
+	if err != nil {
+		t.Fatalf("EthGetLogs: %v", err)
+	}
+
+	got := &events{}
+	if err = icon721.ParseEvents(changes, got, nil); err != nil {
+		t.Fatalf("ParseEvents: %v", err)
+	}
+	want := []string{
+		"OwnershipTransferred " + common.Address{}.Hex() + " " + alice.Hex(),
+		"Transfer " + common.Address{}.Hex() + " " + bob.Hex() + " 42",
+	}
+	if len(got.calls) != len(want) {
+		t.Fatalf("got events %v, want %v", got.calls, want)
+	}
+	for i := range want {
+		if got.calls[i] != want[i] {
+			t.Fatalf("event %d = %q, want %q", i, got.calls[i], want[i])
+		}
+	}
+}
+
+// eventLog returns the log of `event` at block `block`, removed from the chain if `removed` is true
+func eventLog(block uint64, removed bool, event string, args ...interface{}) *eth.FilterChange {
+	log := ethtest.EventLog(icon721Address, icon721.ABI, event, args...)
+	log.BlockNumber = block
+	log.BlockHash = ethtest.BlockHash(block)
+	log.Removed = removed
+	return eth.NewFilterChange(&log)
+}
+
+func TestParseEventsRemoved(t *testing.T) {
+	transfer := func(removed bool) *eth.FilterChange {
+		return eventLog(2, removed, "Transfer", alice, bob, big.NewInt(42))
+	}
+	ownership := eventLog(1, false, "OwnershipTransferred", common.Address{}, alice)
+	transferred := "Transfer " + alice.Hex() + " " + bob.Hex() + " 42"
+
+	tests := []struct {
+		name         string
+		changes      []*eth.FilterChange
+		withRollback bool
+		wantErr      error
+		wantEvents   []string
+		wantRollback []string
+	}{
+		{
+			name:    "without rollback",
+			changes: []*eth.FilterChange{ownership, transfer(false), transfer(true)},
+			wantErr: eth.ErrRemovedEventNotHandled,
+		},
+		{
+			name:         "with rollback",
+			changes:      []*eth.FilterChange{ownership, transfer(false), transfer(true)},
+			withRollback: true,
+			wantEvents:   []string{"OwnershipTransferred " + common.Address{}.Hex() + " " + alice.Hex(), transferred},
+			wantRollback: []string{transferred},
+		},
+		{
+			name:       "removed log of another event",
+			changes:    []*eth.FilterChange{transfer(false), {Topics: []common.Hash{{1}}, Removed: true}},
+			wantEvents: []string{transferred},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, rolledBack := &events{}, &events{}
+			var rollback icon721.Events
+			if test.withRollback {
+				rollback = rolledBack
+			}
+
+			err := icon721.ParseEvents(test.changes, got, rollback)
+			if !stderrors.Is(err, test.wantErr) {
+				t.Fatalf("ParseEvents = %v, want %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(got.calls, test.wantEvents) {
+				t.Fatalf("events %v, want %v", got.calls, test.wantEvents)
+			}
+			if !reflect.DeepEqual(rolledBack.calls, test.wantRollback) {
+				t.Fatalf("rolled back events %v, want %v", rolledBack.calls, test.wantRollback)
+			}
+		})
+	}
+}
+
+func TestMintToRejectsInvalidAmount(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	contract := icon721.NewIcon721Contract(client.Backend(), icon721Address)
+
+	if _, err := contract.MintTo(ethtest.Context(t), alice, 0); err == nil {
+		t.Fatal("MintTo with amount 0 succeeded")
+	}
+	if got := len(node.Requests()); got != 0 {
+		t.Fatalf("MintTo with invalid amount sent %d requests", got)
+	}
+}
@@ -3,6 +3,7 @@ package icon721
 import (
 	"github.com/adene-develop/adene-goeth/contract"
 	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/pkg/errors"
 )
 
 type Events interface {
@@ -10,12 +11,33 @@ type Events interface {
 	contract.OwnableEvents
 }
 
-func ParseEvents(filterChanges []*eth.FilterChange, events Events) error {
-	if err := contract.ParseERC721Events(filterChanges, events); err != nil {
-		return err
+// ParseEvents calls the callback of `events` for each ICON721 event in `filterChanges`, in the order of the logs.
+// A log removed from the chain by a reorg calls the callback of `rollback` instead, to undo the event.
+// If `rollback` is nil and a removed event is found, eth.ErrRemovedEventNotHandled is returned before any callback is called.
+func ParseEvents(filterChanges []*eth.FilterChange, events Events, rollback Events) error {
+	if rollback == nil {
+		err := eth.CheckRemovedEvents(filterChanges, ABI.Events["Transfer"], ABI.Events["Approval"], ABI.Events["ApprovalForAll"], ABI.Events["OwnershipTransferred"])
+		if err != nil {
+			return errors.Wrap(err, "icon721 parse events error")
+		}
 	}
-	if err := contract.ParseOwnableEvents(filterChanges, events); err != nil {
-		return err
+	for _, change := range filterChanges {
+		target := events
+		if change.Removed {
+			target = rollback
+		}
+		if err := parseEvent(change, target); err != nil {
+			return errors.Wrap(err, "icon721 parse events error")
+		}
 	}
 	return nil
 }
+
+// parseEvent calls the callback of `events` for `change` if it is an ICON721 event
+func parseEvent(change *eth.FilterChange, events Events) error {
+	if ok, err := contract.ParseERC721Event(change, events); ok {
+		return err
+	}
+	_, err := contract.ParseOwnableEvent(change, events)
+	return err
+}
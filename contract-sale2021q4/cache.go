@@ -0,0 +1,14 @@
+package sale2021q4
+
+import (
+	"github.com/adene-develop/adene-goeth/contract"
+	"github.com/adene-develop/adene-goeth/eth"
+)
+
+// RegisterCachePolicies đặt chính sách cache cho các hàm view của contract cùng với contract.RegisterCachePolicies
+// level của box không bao giờ thay đổi nên `boxLevelOf` được cache mãi mãi
+func RegisterCachePolicies(cache *eth.ViewCache) {
+	contract.RegisterCachePolicies(cache)
+
+	cache.SetPolicy(ABI, "boxLevelOf", eth.CacheForever)
+}
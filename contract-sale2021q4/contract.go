@@ -4,8 +4,10 @@ import (
 	"context"
 	"github.com/adene-develop/adene-goeth/contract"
 	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
+	"math"
 	"math/big"
 )
 
@@ -17,6 +19,21 @@ type Box struct {
 	Stock       *big.Int
 }
 
+// boxTuple là struct Box trong ABI của contract
+type boxTuple struct {
+	Price       *big.Int
+	TotalSupply uint16
+	Stock       uint16
+}
+
+func (b *boxTuple) box() Box {
+	return Box{
+		Price:       b.Price,
+		TotalSupply: big.NewInt(int64(b.TotalSupply)),
+		Stock:       big.NewInt(int64(b.Stock)),
+	}
+}
+
 type BoxLevel int
 
 const (
@@ -39,12 +56,24 @@ type SALE2021Q4 interface {
 
 	// BoxLevelOf trả về level của 1 box
 	BoxLevelOf(ctx context.Context, tokenID int64) (level BoxLevel, err error)
+
+	// Buy mua `amount` box với level `level`
+	Buy(ctx context.Context, level BoxLevel, amount int) (common.Hash, error)
+
+	// Pause dừng contract, chỉ owner được gọi
+	Pause(ctx context.Context) (common.Hash, error)
+
+	// Unpause mở lại contract, chỉ owner được gọi
+	Unpause(ctx context.Context) (common.Hash, error)
 }
 
-func NewSale2021Q4Contract(client *eth.Client, address common.Address) SALE2021Q4 {
+func NewSale2021Q4Contract(backend eth.Backend, address common.Address, opts ...eth.Option) SALE2021Q4 {
+	client := eth.AsClient(backend, opts...)
 	return &sale2021q4{
-		address: address,
-		client:  client,
+		Pausable: contract.NewPauseable(client.Backend(), address),
+		Ownable:  contract.NewOwnable(client.Backend(), address),
+		address:  address,
+		client:   client,
 	}
 }
 
@@ -64,45 +93,88 @@ func (s *sale2021q4) Client() *eth.Client {
 }
 
 func (s *sale2021q4) Info(ctx context.Context) (tokenAddress common.Address, icon721Address common.Address, commonBox Box, rareBox Box, legendaryBox Box, err error) {
-	var result struct {
-		Token     common.Address
-		Icon721   common.Address
-		Common    Box
-		Rare      Box
-		Legendary Box
-	}
+	ctx, span := s.client.StartSpan(ctx, "sale2021q4", "Info", s.address)
+	defer span.End()
+
+	// outputs của info không có tên nên phải unpack theo thứ tự
+	var result [5]interface{}
 	err = s.client.CallContractViewFunction(ctx, ABI, s.address, &result, "info")
 	if err != nil {
 		return [20]byte{}, [20]byte{}, Box{}, Box{}, Box{}, errors.Wrap(err, "sale2021q4 call view `info` error")
 	}
 
-	return result.Token, result.Icon721, result.Common, result.Rare, result.Legendary, nil
+	tokenAddress = *abi.ConvertType(result[0], new(common.Address)).(*common.Address)
+	icon721Address = *abi.ConvertType(result[1], new(common.Address)).(*common.Address)
+	commonBox = abi.ConvertType(result[2], new(boxTuple)).(*boxTuple).box()
+	rareBox = abi.ConvertType(result[3], new(boxTuple)).(*boxTuple).box()
+	legendaryBox = abi.ConvertType(result[4], new(boxTuple)).(*boxTuple).box()
+	return tokenAddress, icon721Address, commonBox, rareBox, legendaryBox, nil
 }
 
 func (s *sale2021q4) InfoWallet(ctx context.Context, user common.Address) (commonBought int, rareBought int, legendaryBought int, err error) {
-	var result struct {
-		Common    uint16
-		Rare      uint16
-		Legendary uint16
-	}
+	ctx, span := s.client.StartSpan(ctx, "sale2021q4", "InfoWallet", s.address)
+	defer span.End()
+
+	var result [3]uint16
 
 	err = s.client.CallContractViewFunction(ctx, ABI, s.address, &result, "infoWallet", user)
 	if err != nil {
 		return 0, 0, 0, errors.Wrap(err, "sale2021q4 call view `infoWallet` error")
 	}
 
-	return int(result.Common), int(result.Rare), int(result.Legendary), nil
+	return int(result[0]), int(result[1]), int(result[2]), nil
 }
 
 func (s *sale2021q4) BoxLevelOf(ctx context.Context, tokenID int64) (BoxLevel, error) {
-	var result struct {
-		BoxLevel *big.Int
-	}
+	ctx, span := s.client.StartSpan(ctx, "sale2021q4", "BoxLevelOf", s.address)
+	defer span.End()
+
+	var result uint8
 
 	err := s.client.CallContractViewFunction(ctx, ABI, s.address, &result, "boxLevelOf", big.NewInt(tokenID))
 	if err != nil {
 		return 0, errors.Wrap(err, "sale2021q4 BoxLevelOf call view error")
 	}
 
-	return BoxLevel(result.BoxLevel.Int64()), nil
+	return BoxLevel(result), nil
+}
+
+func (s *sale2021q4) Buy(ctx context.Context, level BoxLevel, amount int) (common.Hash, error) {
+	ctx, span := s.client.StartSpan(ctx, "sale2021q4", "Buy", s.address)
+	defer span.End()
+
+	if level < BoxLevelCommon || level > BoxLevelLegendary {
+		return common.Hash{}, eth.RecordSpanError(ctx, errors.Errorf("sale2021q4 invalid box level %d", level))
+	}
+	if amount <= 0 || amount > math.MaxUint16 {
+		return common.Hash{}, eth.RecordSpanError(ctx, errors.Errorf("sale2021q4 invalid buy amount %d", amount))
+	}
+
+	txHash, err := s.client.SendContractTransaction(ctx, ABI, s.address, "buy", uint8(level), uint16(amount))
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "sale2021q4 send `buy` transaction error")
+	}
+	return txHash, nil
+}
+
+func (s *sale2021q4) Pause(ctx context.Context) (common.Hash, error) {
+	ctx, span := s.client.StartSpan(ctx, "sale2021q4", "Pause", s.address)
+	defer span.End()
+
+	txHash, err := s.client.SendContractTransaction(ctx, ABI, s.address, "pause")
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "sale2021q4 send `pause` transaction error")
+	}
+	return txHash, nil
+}
+
+func (s *sale2021q4) Unpause(ctx context.Context) (common.Hash, error) {
+	ctx, span := s.client.StartSpan(ctx, "sale2021q4", "Unpause", s.address)
+	defer span.End()
+
+	txHash, err := s.client.SendContractTransaction(ctx, ABI, s.address, "unpause")
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "sale2021q4 send `unpause` transaction error")
+	}
+	return txHash, nil
 }
@@ -0,0 +1,135 @@
+package sale2021q4_test
+
+import (
+	"github.com/adene-develop/adene-goeth/contract-sale2021q4"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"testing"
+)
+
+var (
+	saleAddress    = common.HexToAddress("0x00000000000000000000000000000000000000a5")
+	tokenAddress   = common.HexToAddress("0x00000000000000000000000000000000000000e2")
+	icon721Address = common.HexToAddress("0x00000000000000000000000000000000000000c7")
+	alice          = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+)
+
+type box struct {
+	Price       *big.Int
+	TotalSupply uint16
+	Stock       uint16
+}
+
+type events struct {
+	calls []string
+}
+
+func (e *events) Paused(account common.Address) {
+	e.calls = append(e.calls, "Paused "+account.Hex())
+}
+
+func (e *events) Unpaused(account common.Address) {
+	e.calls = append(e.calls, "Unpaused "+account.Hex())
+}
+
+func (e *events) OwnershipTransferred(previousOwner common.Address, newOwner common.Address) {
+	e.calls = append(e.calls, "OwnershipTransferred "+previousOwner.Hex()+" "+newOwner.Hex())
+}
+
+func (e *events) Bought(user common.Address, level sale2021q4.BoxLevel, amount int, startTokenID, toTokenID int64) {
+	e.calls = append(e.calls, "Bought "+user.Hex()+" "+big.NewInt(int64(level)).String()+" "+big.NewInt(int64(amount)).String()+
+		" "+big.NewInt(startTokenID).String()+" "+big.NewInt(toTokenID).String())
+}
+
+func TestInfo(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	contract := sale2021q4.NewSale2021Q4Contract(client.Backend(), saleAddress)
+	node.OnCall(saleAddress, sale2021q4.ABI, "info", nil, tokenAddress, icon721Address,
+		box{Price: big.NewInt(100), TotalSupply: 1000, Stock: 900},
+		box{Price: big.NewInt(200), TotalSupply: 100, Stock: 90},
+		box{Price: big.NewInt(300), TotalSupply: 10, Stock: 9})
+
+	token, icon721, commonBox, rareBox, legendaryBox, err := contract.Info(ethtest.Context(t))
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if token != tokenAddress || icon721 != icon721Address {
+		t.Fatalf("Info addresses = %s, %s; want %s, %s", token.Hex(), icon721.Hex(), tokenAddress.Hex(), icon721Address.Hex())
+	}
+	for i, tc := range []struct {
+		got                       sale2021q4.Box
+		price, totalSupply, stock int64
+	}{
+		{commonBox, 100, 1000, 900},
+		{rareBox, 200, 100, 90},
+		{legendaryBox, 300, 10, 9},
+	} {
+		if tc.got.Price.Int64() != tc.price || tc.got.TotalSupply.Int64() != tc.totalSupply || tc.got.Stock.Int64() != tc.stock {
+			t.Fatalf("box %d = %v, %v, %v; want %d, %d, %d", i, tc.got.Price, tc.got.TotalSupply, tc.got.Stock, tc.price, tc.totalSupply, tc.stock)
+		}
+	}
+}
+
+func TestInfoWallet(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	contract := sale2021q4.NewSale2021Q4Contract(client.Backend(), saleAddress)
+	node.OnCall(saleAddress, sale2021q4.ABI, "infoWallet", []interface{}{alice}, uint16(3), uint16(2), uint16(1))
+
+	commonBought, rareBought, legendaryBought, err := contract.InfoWallet(ethtest.Context(t), alice)
+	if err != nil {
+		t.Fatalf("InfoWallet: %v", err)
+	}
+	if commonBought != 3 || rareBought != 2 || legendaryBought != 1 {
+		t.Fatalf("InfoWallet = %d, %d, %d; want 3, 2, 1", commonBought, rareBought, legendaryBought)
+	}
+}
+
+func TestBoxLevelOf(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	contract := sale2021q4.NewSale2021Q4Contract(client.Backend(), saleAddress)
+	node.OnCall(saleAddress, sale2021q4.ABI, "boxLevelOf", []interface{}{big.NewInt(7)}, uint8(sale2021q4.BoxLevelLegendary))
+
+	level, err := contract.BoxLevelOf(ethtest.Context(t), 7)
+	if err != nil {
+		t.Fatalf("BoxLevelOf: %v", err)
+	}
+	if level != sale2021q4.BoxLevelLegendary {
+		t.Fatalf("BoxLevelOf = %d, want %d", level, sale2021q4.BoxLevelLegendary)
+	}
+}
+
+func TestParseEvents(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	contract := sale2021q4.NewSale2021Q4Contract(client.Backend(), saleAddress)
+	node.AddEvent(1, saleAddress, sale2021q4.ABI, "OwnershipTransferred", common.Address{}, alice)
+	node.AddEvent(2, saleAddress, sale2021q4.ABI, sale2021q4.EventBoughtName, alice,
+		uint8(sale2021q4.BoxLevelRare), uint16(2), big.NewInt(10), big.NewInt(11))
+
+	changes, err := contract.Client().EthGetLogs(ethtest.Context(t), eth.FilterQuery{
+		FromBlock: eth.AtBlock(0),
+		ToBlock:   eth.AtBlock(2),
+		Addresses: []common.Address{saleAddress},
+	})
+	if err != nil {
+		t.Fatalf("EthGetLogs: %v", err)
+	}
+
+	got := &events{}
+	if err = sale2021q4.ParseEvents(changes, got, nil); err != nil {
+		t.Fatalf("ParseEvents: %v", err)
+	}
+	want := []string{
+		"OwnershipTransferred " + common.Address{}.Hex() + " " + alice.Hex(),
+		"Bought " + alice.Hex() + " 1 2 10 11",
+	}
+	if len(got.calls) != len(want) {
+		t.Fatalf("got events %v, want %v", got.calls, want)
+	}
+	for i := range want {
+		if got.calls[i] != want[i] {
+			t.Fatalf("event %d = %q, want %q", i, got.calls[i], want[i])
+		}
+	}
+}
@@ -19,7 +19,10 @@ type Events interface {
 	Bought(user common.Address, level BoxLevel, amount int, startTokenID, toTokenID int64)
 }
 
-func ParseEvents(filterChanges []*eth.FilterChange, events Events) error {
+// ParseEvents calls the callback of `events` for each sale event in `filterChanges`, in the order of the logs.
+// A log removed from the chain by a reorg calls the callback of `rollback` instead, to undo the event.
+// If `rollback` is nil and a removed event is found, eth.ErrRemovedEventNotHandled is returned before any callback is called.
+func ParseEvents(filterChanges []*eth.FilterChange, events Events, rollback Events) error {
 	if filterChanges == nil || len(filterChanges) == 0 {
 		return nil
 	}
@@ -28,29 +31,38 @@ func ParseEvents(filterChanges []*eth.FilterChange, events Events) error {
 		return errors.New("events is nil")
 	}
 
-	err := contract.ParsePauseableEvents(filterChanges, events)
-	if err != nil {
-		return errors.Wrap(err, "sale2021q4 parse pauseable events error")
-	}
-
-	err = contract.ParseOwnableEvents(filterChanges, events)
-	if err != nil {
-		return errors.Wrap(err, "sale2021q4 parse ownable events error")
+	if rollback == nil {
+		err := eth.CheckRemovedEvents(filterChanges, ABI.Events["OwnershipTransferred"], ABI.Events[EventBoughtName])
+		if err != nil {
+			return errors.Wrap(err, "sale2021q4 parse events error")
+		}
 	}
-
-	for i := 0; i < len(filterChanges); i++ {
-		switch filterChanges[i].EventID() {
-		case ABI.Events[EventBoughtName].ID:
-			if err = parseBoughtEvent(filterChanges[i], events); err != nil {
-				return err
-			}
-		default:
+	for _, change := range filterChanges {
+		target := events
+		if change.Removed {
+			target = rollback
+		}
+		if err := parseEvent(change, target); err != nil {
+			return errors.Wrap(err, "sale2021q4 parse events error")
 		}
 	}
 	return nil
 }
 
+// parseEvent calls the callback of `events` for `change` if it is a sale event
+func parseEvent(change *eth.FilterChange, events Events) error {
+	if change.EventID() == ABI.Events[EventBoughtName].ID {
+		return parseBoughtEvent(change, events)
+	}
+	// TODO parse the pausable events once contract.ParsePauseableEvents is implemented
+	_, err := contract.ParseOwnableEvent(change, events)
+	return err
+}
+
 func parseBoughtEvent(change *eth.FilterChange, events Events) error {
+	if len(change.Topics) < 2 {
+		return errors.New("invalid topics")
+	}
 	user := common.BytesToAddress(change.Topics[1].Bytes())
 	var event struct {
 		Level        uint8
@@ -62,7 +74,6 @@ func parseBoughtEvent(change *eth.FilterChange, events Events) error {
 	if err != nil {
 		return errors.Wrap(err, "could not unpack bought event data to interface")
 	}
-
 	events.Bought(user, BoxLevel(event.Level), int(event.Amount), event.StartTokenId.Int64(), event.ToTokenId.Int64())
 	return nil
 }
@@ -0,0 +1,27 @@
+package contract
+
+import (
+	"github.com/adene-develop/adene-goeth/eth"
+	"time"
+)
+
+// OwnerCacheTTL is how long RegisterCachePolicies keeps the result of {owner}.
+const OwnerCacheTTL = time.Minute
+
+// RegisterCachePolicies sets the cache policies of the standard contract view functions:
+// {name}, {symbol} and {decimals} of ERC20, {name} and {symbol} of ERC721 never change and are cached forever.
+// {owner} of Ownable is cached for OwnerCacheTTL, and dropped earlier when an {OwnershipTransferred} event of the contract
+// is observed or the client sends a {transferOwnership} or {renounceOwnership} transaction to the contract.
+// The TTL bounds how long a transfer made by another account stays unnoticed when no watcher feeds the cache.
+func RegisterCachePolicies(cache *eth.ViewCache) {
+	cache.SetPolicy(ERC20ABI, "name", eth.CacheForever)
+	cache.SetPolicy(ERC20ABI, "symbol", eth.CacheForever)
+	cache.SetPolicy(ERC20ABI, "decimals", eth.CacheForever)
+	cache.SetPolicy(ERC721ABI, "name", eth.CacheForever)
+	cache.SetPolicy(ERC721ABI, "symbol", eth.CacheForever)
+
+	cache.SetPolicy(OwnableABI, "owner", eth.CacheTTL(OwnerCacheTTL))
+	cache.InvalidateOnEvent(OwnableABI.Events["OwnershipTransferred"], OwnableABI, "owner")
+	cache.InvalidateOnTransaction(OwnableABI, "transferOwnership", "owner")
+	cache.InvalidateOnTransaction(OwnableABI, "renounceOwnership", "owner")
+}
@@ -28,12 +28,26 @@ type ERC20 interface {
 	// allowed to spend on behalf of `owner` through {transferFrom}. This is
 	// zero by default.
 	Allowance(ctx context.Context, owner, spender common.Address) (*big.Int, error)
+
+	// BalancesOf returns the amount of tokens owned by each of `accounts`,
+	// read in batches through the client's {eth.ViewBatcher}.
+	BalancesOf(ctx context.Context, accounts []common.Address) ([]*big.Int, error)
+
+	// Transfer moves `amount` tokens from the client's account to `recipient`.
+	Transfer(ctx context.Context, recipient common.Address, amount *big.Int) (common.Hash, error)
+
+	// Approve sets `amount` as the allowance of `spender` over the client's tokens.
+	Approve(ctx context.Context, spender common.Address, amount *big.Int) (common.Hash, error)
+
+	// TransferFrom moves `amount` tokens from `sender` to `recipient` using the
+	// allowance mechanism. `amount` is then deducted from the client's allowance.
+	TransferFrom(ctx context.Context, sender, recipient common.Address, amount *big.Int) (common.Hash, error)
 }
 
-func NewERC20(client *eth.Client, address common.Address) ERC20 {
+func NewERC20(backend eth.Backend, address common.Address, opts ...eth.Option) ERC20 {
 	return &ERC20Contract{
 		address: address,
-		client:  client,
+		client:  eth.AsClient(backend, opts...),
 	}
 }
 
@@ -43,6 +57,9 @@ type ERC20Contract struct {
 }
 
 func (e *ERC20Contract) Name(ctx context.Context) (string, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC20Contract", "Name", e.address)
+	defer span.End()
+
 	var result struct {
 		Name string
 	}
@@ -55,6 +72,9 @@ func (e *ERC20Contract) Name(ctx context.Context) (string, error) {
 }
 
 func (e *ERC20Contract) Symbol(ctx context.Context) (string, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC20Contract", "Symbol", e.address)
+	defer span.End()
+
 	var result struct {
 		Symbol string
 	}
@@ -67,6 +87,9 @@ func (e *ERC20Contract) Symbol(ctx context.Context) (string, error) {
 }
 
 func (e *ERC20Contract) Decimals(ctx context.Context) (uint8, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC20Contract", "Decimals", e.address)
+	defer span.End()
+
 	var result struct {
 		Decimals uint8
 	}
@@ -78,6 +101,9 @@ func (e *ERC20Contract) Decimals(ctx context.Context) (uint8, error) {
 }
 
 func (e *ERC20Contract) TotalSupply(ctx context.Context) (*big.Int, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC20Contract", "TotalSupply", e.address)
+	defer span.End()
+
 	var result struct {
 		TotalSupply *big.Int
 	}
@@ -90,6 +116,9 @@ func (e *ERC20Contract) TotalSupply(ctx context.Context) (*big.Int, error) {
 }
 
 func (e *ERC20Contract) BalanceOf(ctx context.Context, account common.Address) (*big.Int, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC20Contract", "BalanceOf", e.address)
+	defer span.End()
+
 	var result struct {
 		Balance *big.Int
 	}
@@ -100,6 +129,9 @@ func (e *ERC20Contract) BalanceOf(ctx context.Context, account common.Address) (
 }
 
 func (e *ERC20Contract) Allowance(ctx context.Context, owner, spender common.Address) (*big.Int, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC20Contract", "Allowance", e.address)
+	defer span.End()
+
 	var result struct {
 		Allowance *big.Int
 	}
@@ -109,6 +141,65 @@ func (e *ERC20Contract) Allowance(ctx context.Context, owner, spender common.Add
 	return result.Allowance, nil
 }
 
+func (e *ERC20Contract) BalancesOf(ctx context.Context, accounts []common.Address) ([]*big.Int, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC20Contract", "BalancesOf", e.address)
+	defer span.End()
+
+	results := make([]struct {
+		Balance *big.Int
+	}, len(accounts))
+	calls := make([]*eth.ViewCall, len(accounts))
+	for i, account := range accounts {
+		calls[i] = eth.NewViewCall(ERC20ABI, e.address, &results[i], "balanceOf", account)
+	}
+
+	if err := e.client.ViewBatcher().Call(ctx, calls); err != nil {
+		return nil, errors.Wrap(err, "ERC20Contract batch call `balanceOf` error")
+	}
+
+	balances := make([]*big.Int, len(accounts))
+	for i, call := range calls {
+		if call.Err != nil {
+			return nil, errors.Wrapf(call.Err, "ERC20Contract batch call `balanceOf` of %s error", accounts[i].Hex())
+		}
+		balances[i] = results[i].Balance
+	}
+	return balances, nil
+}
+
+func (e *ERC20Contract) Transfer(ctx context.Context, recipient common.Address, amount *big.Int) (common.Hash, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC20Contract", "Transfer", e.address)
+	defer span.End()
+
+	txHash, err := e.client.SendContractTransaction(ctx, ERC20ABI, e.address, "transfer", recipient, amount)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "ERC20Contract send `transfer` transaction error")
+	}
+	return txHash, nil
+}
+
+func (e *ERC20Contract) Approve(ctx context.Context, spender common.Address, amount *big.Int) (common.Hash, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC20Contract", "Approve", e.address)
+	defer span.End()
+
+	txHash, err := e.client.SendContractTransaction(ctx, ERC20ABI, e.address, "approve", spender, amount)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "ERC20Contract send `approve` transaction error")
+	}
+	return txHash, nil
+}
+
+func (e *ERC20Contract) TransferFrom(ctx context.Context, sender, recipient common.Address, amount *big.Int) (common.Hash, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC20Contract", "TransferFrom", e.address)
+	defer span.End()
+
+	txHash, err := e.client.SendContractTransaction(ctx, ERC20ABI, e.address, "transferFrom", sender, recipient, amount)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "ERC20Contract send `transferFrom` transaction error")
+	}
+	return txHash, nil
+}
+
 type ERC20Events interface {
 	// Transfer emitted when `value` tokens are moved from one account (`from`) to
 	// another (`to`).
@@ -119,23 +210,40 @@ type ERC20Events interface {
 	Approval(owner, spender common.Address, value *big.Int)
 }
 
-func ParseERC20Events(filterChanges []*eth.FilterChange, events ERC20Events) error {
-	for i := 0; i < len(filterChanges); i++ {
-		switch filterChanges[i].EventID() {
-		case ERC20ABI.Events["Transfer"].ID:
-			if err := parseERC20TransferEvent(filterChanges[i], events); err != nil {
-				return errors.Wrap(err, "ParseERC20Events")
-			}
-		case ERC20ABI.Events["Approval"].ID:
-			if err := parseERC20ApprovalEvent(filterChanges[i], events); err != nil {
-				return errors.Wrap(err, "ParseERC20Events")
-			}
-		default:
+// ParseERC20Events calls the callback of `events` for each ERC20 event in `filterChanges`, in the order of the logs.
+// A log removed from the chain by a reorg calls the callback of `rollback` instead, to undo the event.
+// If `rollback` is nil and a removed ERC20 event is found, eth.ErrRemovedEventNotHandled is returned before any callback is called.
+func ParseERC20Events(filterChanges []*eth.FilterChange, events ERC20Events, rollback ERC20Events) error {
+	if rollback == nil {
+		if err := eth.CheckRemovedEvents(filterChanges, ERC20ABI.Events["Transfer"], ERC20ABI.Events["Approval"]); err != nil {
+			return errors.Wrap(err, "ParseERC20Events")
+		}
+	}
+	for _, change := range filterChanges {
+		target := events
+		if change.Removed {
+			target = rollback
+		}
+		if _, err := ParseERC20Event(change, target); err != nil {
+			return errors.Wrap(err, "ParseERC20Events")
 		}
 	}
 	return nil
 }
 
+// ParseERC20Event calls the callback of `events` for `change` if it is an ERC20 event and reports whether it was one.
+// It is used by the ParseEvents of contracts that extend ERC20 to dispatch all their logs in one ordered loop.
+func ParseERC20Event(change *eth.FilterChange, events ERC20Events) (bool, error) {
+	switch change.EventID() {
+	case ERC20ABI.Events["Transfer"].ID:
+		return true, parseERC20TransferEvent(change, events)
+	case ERC20ABI.Events["Approval"].ID:
+		return true, parseERC20ApprovalEvent(change, events)
+	default:
+		return false, nil
+	}
+}
+
 func parseERC20TransferEvent(change *eth.FilterChange, events ERC20Events) error {
 	if change.Topics == nil || len(change.Topics) < 3 {
 		return errors.New("invalid topics")
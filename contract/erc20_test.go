@@ -0,0 +1,159 @@
+package contract_test
+
+import (
+	stderrors "errors"
+	"github.com/adene-develop/adene-goeth/contract"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var (
+	tokenAddress = common.HexToAddress("0x00000000000000000000000000000000000000e2")
+	alice        = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	bob          = common.HexToAddress("0x00000000000000000000000000000000000000b0")
+)
+
+type erc20Event struct {
+	name   string
+	from   common.Address
+	to     common.Address
+	amount *big.Int
+}
+
+type erc20Events struct {
+	events []erc20Event
+}
+
+func (e *erc20Events) Transfer(from, to common.Address, value *big.Int) {
+	e.events = append(e.events, erc20Event{name: "Transfer", from: from, to: to, amount: value})
+}
+
+func (e *erc20Events) Approval(owner, spender common.Address, value *big.Int) {
+	e.events = append(e.events, erc20Event{name: "Approval", from: owner, to: spender, amount: value})
+}
+
+func TestERC20Views(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	node.OnCall(tokenAddress, contract.ERC20ABI, "name", nil, "Adene")
+	node.OnCall(tokenAddress, contract.ERC20ABI, "symbol", nil, "ADENE")
+	node.OnCall(tokenAddress, contract.ERC20ABI, "decimals", nil, uint8(18))
+	node.OnCall(tokenAddress, contract.ERC20ABI, "totalSupply", nil, big.NewInt(1_000_000))
+	node.OnCall(tokenAddress, contract.ERC20ABI, "balanceOf", []interface{}{alice}, big.NewInt(250))
+	node.OnCall(tokenAddress, contract.ERC20ABI, "allowance", []interface{}{alice, bob}, big.NewInt(40))
+
+	token := contract.NewERC20(client.Backend(), tokenAddress)
+	ctx := ethtest.Context(t)
+
+	name, err := token.Name(ctx)
+	if err != nil || name != "Adene" {
+		t.Fatalf("Name = %q, %v; want Adene", name, err)
+	}
+	symbol, err := token.Symbol(ctx)
+	if err != nil || symbol != "ADENE" {
+		t.Fatalf("Symbol = %q, %v; want ADENE", symbol, err)
+	}
+	decimals, err := token.Decimals(ctx)
+	if err != nil || decimals != 18 {
+		t.Fatalf("Decimals = %d, %v; want 18", decimals, err)
+	}
+	totalSupply, err := token.TotalSupply(ctx)
+	if err != nil || totalSupply.Int64() != 1_000_000 {
+		t.Fatalf("TotalSupply = %v, %v; want 1000000", totalSupply, err)
+	}
+	balance, err := token.BalanceOf(ctx, alice)
+	if err != nil || balance.Int64() != 250 {
+		t.Fatalf("BalanceOf = %v, %v; want 250", balance, err)
+	}
+	allowance, err := token.Allowance(ctx, alice, bob)
+	if err != nil || allowance.Int64() != 40 {
+		t.Fatalf("Allowance = %v, %v; want 40", allowance, err)
+	}
+}
+
+func TestParseERC20EventsFromFilter(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	ctx := ethtest.Context(t)
+
+	watcher, err := client.NewWatcher(ctx, eth.FilterQuery{Addresses: []common.Address{tokenAddress}}, time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+	defer watcher.Close(ctx)
+
+	node.AddEvent(1, tokenAddress, contract.ERC20ABI, "Transfer", alice, bob, big.NewInt(10))
+	node.AddEvent(1, tokenAddress, contract.ERC20ABI, "Approval", alice, bob, big.NewInt(5))
+	node.AddEvent(2, common.HexToAddress("0xff"), contract.ERC20ABI, "Transfer", bob, alice, big.NewInt(99))
+
+	changes, err := watcher.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+
+	events := &erc20Events{}
+	if err = contract.ParseERC20Events(changes, events, nil); err != nil {
+		t.Fatalf("ParseERC20Events: %v", err)
+	}
+	want := []erc20Event{
+		{name: "Transfer", from: alice, to: bob, amount: big.NewInt(10)},
+		{name: "Approval", from: alice, to: bob, amount: big.NewInt(5)},
+	}
+	if len(events.events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events.events), len(want))
+	}
+	for i, event := range events.events {
+		if event.name != want[i].name || event.from != want[i].from || event.to != want[i].to || event.amount.Cmp(want[i].amount) != 0 {
+			t.Fatalf("event %d = %+v, want %+v", i, event, want[i])
+		}
+	}
+}
+
+// ledger applies ERC20 transfers to balances, the rollback ledger has sign -1 and undoes them
+type ledger struct {
+	balances map[common.Address]int64
+	sign     int64
+}
+
+func (l *ledger) Transfer(from, to common.Address, value *big.Int) {
+	l.balances[from] -= l.sign * value.Int64()
+	l.balances[to] += l.sign * value.Int64()
+}
+
+func (l *ledger) Approval(owner, spender common.Address, value *big.Int) {}
+
+func TestParseERC20EventsRemoved(t *testing.T) {
+	log := ethtest.EventLog(tokenAddress, contract.ERC20ABI, "Transfer", alice, bob, big.NewInt(10))
+	log.BlockNumber = 1
+	log.BlockHash = ethtest.BlockHash(1)
+	applied := eth.NewFilterChange(&log)
+	log.Removed = true
+	removed := eth.NewFilterChange(&log)
+
+	// without a rollback receiver nothing is applied, not even the logs before the removed one
+	events := &erc20Events{}
+	err := contract.ParseERC20Events([]*eth.FilterChange{applied, removed}, events, nil)
+	if !stderrors.Is(err, eth.ErrRemovedEventNotHandled) {
+		t.Fatalf("ParseERC20Events without rollback = %v, want ErrRemovedEventNotHandled", err)
+	}
+	if len(events.events) != 0 {
+		t.Fatalf("ParseERC20Events without rollback applied %d events", len(events.events))
+	}
+
+	balances := make(map[common.Address]int64)
+	apply, rollback := &ledger{balances: balances, sign: 1}, &ledger{balances: balances, sign: -1}
+	if err = contract.ParseERC20Events([]*eth.FilterChange{applied}, apply, rollback); err != nil {
+		t.Fatalf("ParseERC20Events: %v", err)
+	}
+	if balances[bob] != 10 || balances[alice] != -10 {
+		t.Fatalf("balances after transfer = %v", balances)
+	}
+	if err = contract.ParseERC20Events([]*eth.FilterChange{removed}, apply, rollback); err != nil {
+		t.Fatalf("ParseERC20Events removed: %v", err)
+	}
+	if balances[bob] != 0 || balances[alice] != 0 {
+		t.Fatalf("balances after rollback = %v", balances)
+	}
+}
@@ -31,12 +31,25 @@ type ERC721 interface {
 
 	// IsApprovedForAll returns if the `operator` is allowed to manage all of the assets of `owner`.
 	IsApprovedForAll(ctx context.Context, owner common.Address, operator common.Address) (bool, error)
+
+	// Approve gives permission to `to` to transfer `tokenId` token to another account.
+	Approve(ctx context.Context, to common.Address, tokenID int64) (common.Hash, error)
+
+	// SetApprovalForAll approves or removes `operator` as an operator for the client's account.
+	SetApprovalForAll(ctx context.Context, operator common.Address, approved bool) (common.Hash, error)
+
+	// TransferFrom transfers `tokenId` token from `from` to `to`.
+	TransferFrom(ctx context.Context, from common.Address, to common.Address, tokenID int64) (common.Hash, error)
+
+	// SafeTransferFrom safely transfers `tokenId` token from `from` to `to`, checking first that
+	// contract recipients are aware of the ERC721 protocol to prevent tokens from being forever locked.
+	SafeTransferFrom(ctx context.Context, from common.Address, to common.Address, tokenID int64) (common.Hash, error)
 }
 
-func NewERC721(client *eth.Client, address common.Address) ERC721 {
+func NewERC721(backend eth.Backend, address common.Address, opts ...eth.Option) ERC721 {
 	return &ERC721Contract{
 		address: address,
-		client:  client,
+		client:  eth.AsClient(backend, opts...),
 	}
 }
 
@@ -54,6 +67,9 @@ func (e *ERC721Contract) Client() *eth.Client {
 }
 
 func (e *ERC721Contract) Name(ctx context.Context) (string, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721Contract", "Name", e.address)
+	defer span.End()
+
 	var result struct {
 		Name string
 	}
@@ -64,6 +80,9 @@ func (e *ERC721Contract) Name(ctx context.Context) (string, error) {
 }
 
 func (e *ERC721Contract) Symbol(ctx context.Context) (string, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721Contract", "Symbol", e.address)
+	defer span.End()
+
 	var result struct {
 		Symbol string
 	}
@@ -75,6 +94,9 @@ func (e *ERC721Contract) Symbol(ctx context.Context) (string, error) {
 }
 
 func (e *ERC721Contract) TokenURI(ctx context.Context, tokenID int64) (string, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721Contract", "TokenURI", e.address)
+	defer span.End()
+
 	var result struct {
 		TokenURI string
 	}
@@ -85,6 +107,9 @@ func (e *ERC721Contract) TokenURI(ctx context.Context, tokenID int64) (string, e
 }
 
 func (e *ERC721Contract) BalanceOf(ctx context.Context, owner common.Address) (int64, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721Contract", "BalanceOf", e.address)
+	defer span.End()
+
 	var result struct {
 		Balance *big.Int
 	}
@@ -96,6 +121,9 @@ func (e *ERC721Contract) BalanceOf(ctx context.Context, owner common.Address) (i
 }
 
 func (e *ERC721Contract) OwnerOf(ctx context.Context, tokenID int64) (common.Address, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721Contract", "OwnerOf", e.address)
+	defer span.End()
+
 	var result struct {
 		Owner common.Address
 	}
@@ -106,6 +134,9 @@ func (e *ERC721Contract) OwnerOf(ctx context.Context, tokenID int64) (common.Add
 }
 
 func (e *ERC721Contract) GetApproved(ctx context.Context, tokenID int64) (common.Address, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721Contract", "GetApproved", e.address)
+	defer span.End()
+
 	var result struct {
 		Operator common.Address
 	}
@@ -116,6 +147,9 @@ func (e *ERC721Contract) GetApproved(ctx context.Context, tokenID int64) (common
 }
 
 func (e *ERC721Contract) IsApprovedForAll(ctx context.Context, owner common.Address, operator common.Address) (bool, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721Contract", "IsApprovedForAll", e.address)
+	defer span.End()
+
 	var result struct {
 		IsApprovedForAll bool
 	}
@@ -125,6 +159,50 @@ func (e *ERC721Contract) IsApprovedForAll(ctx context.Context, owner common.Addr
 	return result.IsApprovedForAll, nil
 }
 
+func (e *ERC721Contract) Approve(ctx context.Context, to common.Address, tokenID int64) (common.Hash, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721Contract", "Approve", e.address)
+	defer span.End()
+
+	txHash, err := e.client.SendContractTransaction(ctx, ERC721ABI, e.address, "approve", to, big.NewInt(tokenID))
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "ERC721Contract send `approve` transaction error")
+	}
+	return txHash, nil
+}
+
+func (e *ERC721Contract) SetApprovalForAll(ctx context.Context, operator common.Address, approved bool) (common.Hash, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721Contract", "SetApprovalForAll", e.address)
+	defer span.End()
+
+	txHash, err := e.client.SendContractTransaction(ctx, ERC721ABI, e.address, "setApprovalForAll", operator, approved)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "ERC721Contract send `setApprovalForAll` transaction error")
+	}
+	return txHash, nil
+}
+
+func (e *ERC721Contract) TransferFrom(ctx context.Context, from common.Address, to common.Address, tokenID int64) (common.Hash, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721Contract", "TransferFrom", e.address)
+	defer span.End()
+
+	txHash, err := e.client.SendContractTransaction(ctx, ERC721ABI, e.address, "transferFrom", from, to, big.NewInt(tokenID))
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "ERC721Contract send `transferFrom` transaction error")
+	}
+	return txHash, nil
+}
+
+func (e *ERC721Contract) SafeTransferFrom(ctx context.Context, from common.Address, to common.Address, tokenID int64) (common.Hash, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721Contract", "SafeTransferFrom", e.address)
+	defer span.End()
+
+	txHash, err := e.client.SendContractTransaction(ctx, ERC721ABI, e.address, "safeTransferFrom", from, to, big.NewInt(tokenID))
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "ERC721Contract send `safeTransferFrom` transaction error")
+	}
+	return txHash, nil
+}
+
 type ERC721Events interface {
 	// Transfer emitted when `tokenId` token is transferred from `from` to `to`.
 	Transfer(from common.Address, to common.Address, tokenID int64)
@@ -136,24 +214,42 @@ type ERC721Events interface {
 	ApprovalForAll(owner common.Address, operator common.Address, approved bool)
 }
 
-func ParseERC721Events(filterChanges []*eth.FilterChange, events ERC721Events) error {
-	for i := 0; i < len(filterChanges); i++ {
-		switch filterChanges[i].EventID() {
-		case ERC721ABI.Events["Transfer"].ID:
-			if err := parseERC721TransferEvent(filterChanges[i], events); err != nil {
-				return errors.Wrap(err, "ParseERC721Events parse transfer event")
-			}
-		case ERC721ABI.Events["Approval"].ID:
-			if err := parseERC721ApprovalEvent(filterChanges[i], events); err != nil {
-				return errors.Wrap(err, "ParseERC721Events parse approval event")
-			}
-
-		default:
+// ParseERC721Events calls the callback of `events` for each ERC721 event in `filterChanges`, in the order of the logs.
+// A log removed from the chain by a reorg calls the callback of `rollback` instead, to undo the event.
+// If `rollback` is nil and a removed ERC721 event is found, eth.ErrRemovedEventNotHandled is returned before any callback is called.
+func ParseERC721Events(filterChanges []*eth.FilterChange, events ERC721Events, rollback ERC721Events) error {
+	if rollback == nil {
+		if err := eth.CheckRemovedEvents(filterChanges, ERC721ABI.Events["Transfer"], ERC721ABI.Events["Approval"], ERC721ABI.Events["ApprovalForAll"]); err != nil {
+			return errors.Wrap(err, "ParseERC721Events")
+		}
+	}
+	for _, change := range filterChanges {
+		target := events
+		if change.Removed {
+			target = rollback
+		}
+		if _, err := ParseERC721Event(change, target); err != nil {
+			return errors.Wrap(err, "ParseERC721Events")
 		}
 	}
 	return nil
 }
 
+// ParseERC721Event calls the callback of `events` for `change` if it is an ERC721 event and reports whether it was one.
+// It is used by the ParseEvents of contracts that extend ERC721 to dispatch all their logs in one ordered loop.
+func ParseERC721Event(change *eth.FilterChange, events ERC721Events) (bool, error) {
+	switch change.EventID() {
+	case ERC721ABI.Events["Transfer"].ID:
+		return true, errors.Wrap(parseERC721TransferEvent(change, events), "parse transfer event")
+	case ERC721ABI.Events["Approval"].ID:
+		return true, errors.Wrap(parseERC721ApprovalEvent(change, events), "parse approval event")
+	case ERC721ABI.Events["ApprovalForAll"].ID:
+		return true, errors.Wrap(parseERC721ApprovalForAllEvent(change, events), "parse approval for all event")
+	default:
+		return false, nil
+	}
+}
+
 func parseERC721TransferEvent(change *eth.FilterChange, events ERC721Events) error {
 	if change.Topics == nil || len(change.Topics) < 4 {
 		return errors.New("invalid topics")
@@ -189,7 +285,6 @@ func parseERC721ApprovalForAllEvent(change *eth.FilterChange, events ERC721Event
 	if err != nil {
 		return err
 	}
-
 	events.ApprovalForAll(from, to, r.Approved)
 	return nil
 }
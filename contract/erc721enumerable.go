@@ -8,6 +8,10 @@ import (
 	"math/big"
 )
 
+// MaxTokensOfOwner is the largest balance TokensOfOwner enumerates, a larger balance is rejected
+// instead of allocating and batching that many calls.
+const MaxTokensOfOwner = 10_000
+
 // ERC721Enumerable view functions
 type ERC721Enumerable interface {
 	ERC721
@@ -21,11 +25,22 @@ type ERC721Enumerable interface {
 	// TokenByIndex returns a token ID at a given `index` of all the tokens stored by the contract.
 	// Use along with {totalSupply} to enumerate all tokens.
 	TokenByIndex(ctx context.Context, index int64) (tokenID int64, err error)
+
+	// TokensOfOwner returns all token IDs owned by `owner`, enumerated in batches through
+	// the client's {eth.ViewBatcher} instead of one {tokenOfOwnerByIndex} call per token.
+	// The balance and every index are read at the same block, the latest block unless `ctx`
+	// selects one with {eth.ContextAtBlock}. Balances above MaxTokensOfOwner are rejected.
+	TokensOfOwner(ctx context.Context, owner common.Address) (tokenIDs []int64, err error)
+
+	// TokenURIs returns the Uniform Resource Identifier (URI) of each of `tokenIDs`,
+	// read in batches through the client's {eth.ViewBatcher}.
+	TokenURIs(ctx context.Context, tokenIDs []int64) (tokenURIs []string, err error)
 }
 
-func NewERC721Enumerable(client *eth.Client, address common.Address) ERC721Enumerable {
+func NewERC721Enumerable(backend eth.Backend, address common.Address, opts ...eth.Option) ERC721Enumerable {
+	client := eth.AsClient(backend, opts...)
 	return &ERC721EnumerableContract{
-		ERC721:  NewERC721(client, address),
+		ERC721:  NewERC721(client.Backend(), address),
 		address: address,
 		client:  client,
 	}
@@ -38,6 +53,9 @@ type ERC721EnumerableContract struct {
 }
 
 func (e *ERC721EnumerableContract) TokenOfOwnerByIndex(ctx context.Context, owner common.Address, index int64) (int64, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721EnumerableContract", "TokenOfOwnerByIndex", e.address)
+	defer span.End()
+
 	var result struct {
 		TokenID *big.Int
 	}
@@ -49,6 +67,9 @@ func (e *ERC721EnumerableContract) TokenOfOwnerByIndex(ctx context.Context, owne
 }
 
 func (e *ERC721EnumerableContract) TotalSupply(ctx context.Context) (int64, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721EnumerableContract", "TotalSupply", e.address)
+	defer span.End()
+
 	var result struct {
 		TotalSupply *big.Int
 	}
@@ -60,6 +81,9 @@ func (e *ERC721EnumerableContract) TotalSupply(ctx context.Context) (int64, erro
 }
 
 func (e *ERC721EnumerableContract) TokenByIndex(ctx context.Context, index int64) (tokenID int64, err error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721EnumerableContract", "TokenByIndex", e.address)
+	defer span.End()
+
 	var result struct {
 		TokenID *big.Int
 	}
@@ -69,3 +93,72 @@ func (e *ERC721EnumerableContract) TokenByIndex(ctx context.Context, index int64
 	}
 	return result.TokenID.Int64(), nil
 }
+
+func (e *ERC721EnumerableContract) TokensOfOwner(ctx context.Context, owner common.Address) ([]int64, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721EnumerableContract", "TokensOfOwner", e.address)
+	defer span.End()
+
+	// a transfer between the balance and the index calls would otherwise skip or repeat tokens
+	if eth.BlockFromContext(ctx).IsLatest() {
+		head, err := e.client.BlockNumber(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "ERC721EnumerableContract get block number error")
+		}
+		ctx = eth.ContextAtBlock(ctx, eth.AtBlock(head))
+	}
+
+	balance, err := e.BalanceOf(ctx, owner)
+	if err != nil {
+		return nil, errors.Wrap(err, "ERC721EnumerableContract get balance error")
+	}
+	if balance < 0 || balance > MaxTokensOfOwner {
+		return nil, errors.Errorf("ERC721EnumerableContract balance %d of %s is out of range", balance, owner.Hex())
+	}
+
+	results := make([]struct {
+		TokenID *big.Int
+	}, balance)
+	calls := make([]*eth.ViewCall, balance)
+	for i := range calls {
+		calls[i] = eth.NewViewCall(ERC721EnumerableABI, e.address, &results[i], "tokenOfOwnerByIndex", owner, big.NewInt(int64(i)))
+	}
+
+	if err = e.client.ViewBatcher().Call(ctx, calls); err != nil {
+		return nil, errors.Wrap(err, "ERC721EnumerableContract batch call `tokenOfOwnerByIndex` error")
+	}
+
+	tokenIDs := make([]int64, balance)
+	for i, call := range calls {
+		if call.Err != nil {
+			return nil, errors.Wrapf(call.Err, "ERC721EnumerableContract batch call `tokenOfOwnerByIndex` at index %d error", i)
+		}
+		tokenIDs[i] = results[i].TokenID.Int64()
+	}
+	return tokenIDs, nil
+}
+
+func (e *ERC721EnumerableContract) TokenURIs(ctx context.Context, tokenIDs []int64) ([]string, error) {
+	ctx, span := e.client.StartSpan(ctx, "ERC721EnumerableContract", "TokenURIs", e.address)
+	defer span.End()
+
+	results := make([]struct {
+		TokenURI string
+	}, len(tokenIDs))
+	calls := make([]*eth.ViewCall, len(tokenIDs))
+	for i, tokenID := range tokenIDs {
+		calls[i] = eth.NewViewCall(ERC721EnumerableABI, e.address, &results[i], "tokenURI", big.NewInt(tokenID))
+	}
+
+	if err := e.client.ViewBatcher().Call(ctx, calls); err != nil {
+		return nil, errors.Wrap(err, "ERC721EnumerableContract batch call `tokenURI` error")
+	}
+
+	tokenURIs := make([]string, len(tokenIDs))
+	for i, call := range calls {
+		if call.Err != nil {
+			return nil, errors.Wrapf(call.Err, "ERC721EnumerableContract batch call `tokenURI` of token %d error", tokenIDs[i])
+		}
+		tokenURIs[i] = results[i].TokenURI
+	}
+	return tokenURIs, nil
+}
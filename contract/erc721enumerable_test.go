@@ -0,0 +1,75 @@
+package contract_test
+
+import (
+	"encoding/json"
+	"github.com/adene-develop/adene-goeth/contract"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestERC721EnumerableTokensOfOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance int64
+		block   *eth.BlockTag
+		want    []int64
+		// wantBlock is the block parameter every eth_call must carry
+		wantBlock string
+		wantErr   bool
+	}{
+		{name: "pinned to head", balance: 3, want: []int64{10, 11, 12}, wantBlock: `"0x2a"`},
+		{name: "explicit block", balance: 2, block: blockTag(eth.AtBlock(7)), want: []int64{10, 11}, wantBlock: `"0x7"`},
+		{name: "no tokens", balance: 0, want: []int64{}, wantBlock: `"0x2a"`},
+		{name: "balance above cap", balance: contract.MaxTokensOfOwner + 1, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, node := ethtest.NewClient(t, eth.WithoutMulticall())
+			node.SetHead(42)
+			node.OnCall(tokenAddress, contract.ERC721EnumerableABI, "balanceOf", []interface{}{alice}, big.NewInt(test.balance))
+			for i := range test.want {
+				node.OnCall(tokenAddress, contract.ERC721EnumerableABI, "tokenOfOwnerByIndex", []interface{}{alice, big.NewInt(int64(i))}, big.NewInt(int64(10+i)))
+			}
+
+			ctx := ethtest.Context(t)
+			if test.block != nil {
+				ctx = eth.ContextAtBlock(ctx, *test.block)
+			}
+			token := contract.NewERC721Enumerable(client.Backend(), tokenAddress)
+			tokenIDs, err := token.TokensOfOwner(ctx, alice)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("TokensOfOwner = %v, want error", tokenIDs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TokensOfOwner: %v", err)
+			}
+			if !reflect.DeepEqual(tokenIDs, test.want) {
+				t.Fatalf("TokensOfOwner = %v, want %v", tokenIDs, test.want)
+			}
+
+			for _, request := range node.Requests() {
+				if request.Method != "eth_call" {
+					continue
+				}
+				var params []json.RawMessage
+				if err = json.Unmarshal(request.Params, &params); err != nil || len(params) != 2 {
+					t.Fatalf("eth_call params = %s", request.Params)
+				}
+				if string(params[1]) != test.wantBlock {
+					t.Fatalf("eth_call at block %s, want %s", params[1], test.wantBlock)
+				}
+			}
+		})
+	}
+}
+
+func blockTag(block eth.BlockTag) *eth.BlockTag {
+	return &block
+}
@@ -10,11 +10,18 @@ import (
 type Ownable interface {
 	// Owner returns the address of the current owner.
 	Owner(ctx context.Context) (common.Address, error)
+
+	// TransferOwnership transfers ownership of the contract to a new account (`newOwner`).
+	// Can only be called by the current owner.
+	TransferOwnership(ctx context.Context, newOwner common.Address) (common.Hash, error)
+
+	// RenounceOwnership leaves the contract without owner. Can only be called by the current owner.
+	RenounceOwnership(ctx context.Context) (common.Hash, error)
 }
 
-func NewOwnable(client *eth.Client, address common.Address) Ownable {
+func NewOwnable(backend eth.Backend, address common.Address, opts ...eth.Option) Ownable {
 	return &OwnableContract{
-		client:  client,
+		client:  eth.AsClient(backend, opts...),
 		address: address,
 	}
 }
@@ -25,6 +32,9 @@ type OwnableContract struct {
 }
 
 func (o *OwnableContract) Owner(ctx context.Context) (common.Address, error) {
+	ctx, span := o.client.StartSpan(ctx, "OwnableContract", "Owner", o.address)
+	defer span.End()
+
 	var result struct {
 		Owner common.Address
 	}
@@ -35,22 +45,66 @@ func (o *OwnableContract) Owner(ctx context.Context) (common.Address, error) {
 	return result.Owner, nil
 }
 
+func (o *OwnableContract) TransferOwnership(ctx context.Context, newOwner common.Address) (common.Hash, error) {
+	ctx, span := o.client.StartSpan(ctx, "OwnableContract", "TransferOwnership", o.address)
+	defer span.End()
+
+	txHash, err := o.client.SendContractTransaction(ctx, OwnableABI, o.address, "transferOwnership", newOwner)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "OwnableContract send `transferOwnership` error")
+	}
+	return txHash, nil
+}
+
+func (o *OwnableContract) RenounceOwnership(ctx context.Context) (common.Hash, error) {
+	ctx, span := o.client.StartSpan(ctx, "OwnableContract", "RenounceOwnership", o.address)
+	defer span.End()
+
+	txHash, err := o.client.SendContractTransaction(ctx, OwnableABI, o.address, "renounceOwnership")
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "OwnableContract send `renounceOwnership` error")
+	}
+	return txHash, nil
+}
+
 type OwnableEvents interface {
 	OwnershipTransferred(previousOwner common.Address, newOwner common.Address)
 }
 
-func ParseOwnableEvents(filterChanges []*eth.FilterChange, events OwnableEvents) error {
-	for i := 0; i < len(filterChanges); i++ {
-		switch filterChanges[i].EventID() {
-		case OwnableABI.Events["OwnershipTransferred"].ID:
-			if filterChanges[i].Topics == nil || len(filterChanges[i].Topics) < 3 {
-				return errors.New("invalid topics")
-			}
-			previousOwner := common.BytesToAddress(filterChanges[i].Topics[1].Bytes())
-			newOwner := common.BytesToAddress(filterChanges[i].Topics[2].Bytes())
-			events.OwnershipTransferred(previousOwner, newOwner)
-		default:
+// ParseOwnableEvents calls the callback of `events` for each Ownable event in `filterChanges`, in the order of the logs.
+// A log removed from the chain by a reorg calls the callback of `rollback` instead, to undo the event.
+// If `rollback` is nil and a removed Ownable event is found, eth.ErrRemovedEventNotHandled is returned before any callback is called.
+func ParseOwnableEvents(filterChanges []*eth.FilterChange, events OwnableEvents, rollback OwnableEvents) error {
+	if rollback == nil {
+		if err := eth.CheckRemovedEvents(filterChanges, OwnableABI.Events["OwnershipTransferred"]); err != nil {
+			return errors.Wrap(err, "ParseOwnableEvents")
+		}
+	}
+	for _, change := range filterChanges {
+		target := events
+		if change.Removed {
+			target = rollback
+		}
+		if _, err := ParseOwnableEvent(change, target); err != nil {
+			return errors.Wrap(err, "ParseOwnableEvents")
 		}
 	}
 	return nil
 }
+
+// ParseOwnableEvent calls the callback of `events` for `change` if it is an Ownable event and reports whether it was one.
+// It is used by the ParseEvents of contracts that extend Ownable to dispatch all their logs in one ordered loop.
+func ParseOwnableEvent(change *eth.FilterChange, events OwnableEvents) (bool, error) {
+	switch change.EventID() {
+	case OwnableABI.Events["OwnershipTransferred"].ID:
+		if change.Topics == nil || len(change.Topics) < 3 {
+			return true, errors.New("invalid topics")
+		}
+		previousOwner := common.BytesToAddress(change.Topics[1].Bytes())
+		newOwner := common.BytesToAddress(change.Topics[2].Bytes())
+		events.OwnershipTransferred(previousOwner, newOwner)
+		return true, nil
+	default:
+		return false, nil
+	}
+}
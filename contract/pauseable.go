@@ -11,9 +11,9 @@ type Pausable interface {
 	Paused(ctx context.Context) (bool, error)
 }
 
-func NewPauseable(client *eth.Client, address common.Address) Pausable {
+func NewPauseable(backend eth.Backend, address common.Address, opts ...eth.Option) Pausable {
 	return &PauseableContract{
-		client:  client,
+		client:  eth.AsClient(backend, opts...),
 		address: address,
 	}
 }
@@ -36,6 +36,6 @@ type PausableEvents interface {
 	Unpaused(account common.Address)
 }
 
-func ParsePauseableEvents(filterChanges []*eth.FilterChange, events PausableEvents) error {
+func ParsePauseableEvents(filterChanges []*eth.FilterChange, events PausableEvents, rollback PausableEvents) error {
 	return nil
 }
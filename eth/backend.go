@@ -0,0 +1,203 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+// ErrNoRPC được trả về khi gọi các hàm JSON-RPC riêng (filter, batch, fee history) trên client tạo bằng NewBackendClient
+var ErrNoRPC = errors.New("client has no json-rpc connection")
+
+// Backend là các hàm chain mà contract wrapper cần, giống bind.ContractBackend của go-ethereum
+// ethclient.Client, backends.SimulatedBackend và Backend của Client (Client.Backend) đều thoả mãn Backend
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	TransactionByHash(ctx context.Context, txHash common.Hash) (tx *types.Transaction, isPending bool, err error)
+}
+
+// WithChainID đặt chain id của mạng, dùng cho Backend không có `ChainID` như backends.SimulatedBackend
+func WithChainID(chainID *big.Int) Option {
+	return func(c *Client) {
+		c.chainID = chainID
+	}
+}
+
+// NewBackendClient tạo client gửi mọi lời gọi qua `backend`, ví dụ backends.SimulatedBackend để test không cần node
+// client không có kết nối JSON-RPC nên không dùng được filter, JSON-RPC batch và Multicall3 (ViewBatcher gọi lần lượt từng hàm)
+func NewBackendClient(backend Backend, opts ...Option) *Client {
+	c := newClient(append([]Option{WithoutMulticall()}, opts...))
+	c.backend = backend
+	return c.initDefaults()
+}
+
+// AsClient trả về client của `backend` nếu `backend` là Backend của một Client, ngược lại tạo client mới
+// bằng NewBackendClient với `opts`, ví dụ WithPrivateKey và WithChainID để gửi transaction
+// `opts` không áp dụng cho client đã có. Dùng trong constructor của các contract wrapper
+func AsClient(backend Backend, opts ...Option) *Client {
+	if b, ok := backend.(*clientBackend); ok {
+		return b.Client
+	}
+	return NewBackendClient(backend, opts...)
+}
+
+// Backend trả về Backend dùng client, để truyền vào constructor của contract wrapper hoặc các hàm bind của go-ethereum
+func (c *Client) Backend() Backend {
+	return &clientBackend{Client: c}
+}
+
+// clientBackend thêm SendTransaction của bind.ContractTransactor cho Client
+type clientBackend struct {
+	*Client
+}
+
+// SendTransaction thoả mãn Backend, gửi transaction `tx` đã ký sẵn, nonce của `tx` không qua NonceManager của client
+// để client tự cấp nonce, tính gas và ký transaction thì dùng Client.SendTransaction hoặc Client.SendContractTransaction
+func (b *clientBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.backend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	if b.cache != nil && tx.To() != nil {
+		b.cache.observeTransaction(tx.Hash(), *tx.To(), tx.Data())
+	}
+	return nil
+}
+
+// CodeAt thoả mãn Backend
+func (c *Client) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.backend.CodeAt(ctx, contract, blockNumber)
+}
+
+// CallContract thoả mãn Backend, giống CallContractAt tại block `blockNumber`, nil là block mới nhất
+func (c *Client) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return c.CallContractAt(ctx, call, AtBlockNumber(blockNumber))
+}
+
+// HeaderByNumber thoả mãn Backend
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return c.backend.HeaderByNumber(ctx, number)
+}
+
+// PendingCodeAt thoả mãn Backend
+func (c *Client) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return c.backend.PendingCodeAt(ctx, account)
+}
+
+// PendingNonceAt thoả mãn Backend, trả về nonce của node chứ không phải nonce của NonceManager
+func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return c.backend.PendingNonceAt(ctx, account)
+}
+
+// SuggestGasPrice thoả mãn Backend
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return c.backend.SuggestGasPrice(ctx)
+}
+
+// SuggestGasTipCap thoả mãn Backend
+func (c *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return c.backend.SuggestGasTipCap(ctx)
+}
+
+// EstimateGas thoả mãn Backend
+func (c *Client) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return c.backend.EstimateGas(ctx, call)
+}
+
+// FilterLogs thoả mãn Backend
+func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return c.backend.FilterLogs(ctx, query)
+}
+
+// SubscribeFilterLogs thoả mãn Backend
+func (c *Client) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return c.backend.SubscribeFilterLogs(ctx, query, ch)
+}
+
+// TransactionReceipt thoả mãn Backend
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return c.backend.TransactionReceipt(ctx, txHash)
+}
+
+// NonceAt thoả mãn Backend
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return c.backend.NonceAt(ctx, account, blockNumber)
+}
+
+// TransactionByHash thoả mãn Backend
+func (c *Client) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	return c.backend.TransactionByHash(ctx, txHash)
+}
+
+// BlockNumber trả về số của block mới nhất, dùng cùng ContextAtBlock để các lời gọi view đọc cùng một block
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	var head uint64
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		head, err = c.blockNumber(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "client get block number error")
+	}
+	return head, nil
+}
+
+// blockNumber trả về số của block mới nhất
+func (c *Client) blockNumber(ctx context.Context) (uint64, error) {
+	if c.eth != nil {
+		return c.eth.BlockNumber(ctx)
+	}
+
+	header, err := c.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
+// callBackend thực hiện CallContractAt qua Backend khi client không có kết nối JSON-RPC
+func (c *Client) callBackend(ctx context.Context, msg ethereum.CallMsg, block BlockTag) ([]byte, error) {
+	switch {
+	case block.IsPending():
+		caller, ok := c.backend.(bind.PendingContractCaller)
+		if !ok {
+			return nil, errors.New("backend does not support pending state call")
+		}
+		return caller.PendingCallContract(ctx, msg)
+	case block.IsLatest():
+		return c.backend.CallContract(ctx, msg, nil)
+	case block.Number() != nil:
+		return c.backend.CallContract(ctx, msg, block.Number())
+	case block.tag == "earliest":
+		return c.backend.CallContract(ctx, msg, new(big.Int))
+	default:
+		return nil, errors.Errorf("backend does not support call at block %s", block)
+	}
+}
+
+// sequentialBatcher thực hiện các ViewCall lần lượt, dùng cho client không có kết nối JSON-RPC
+type sequentialBatcher struct {
+	client *Client
+}
+
+func (b *sequentialBatcher) Call(ctx context.Context, calls []*ViewCall) error {
+	for _, call := range calls {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		call.Err = b.client.CallContractViewFunction(ctx, call.ABI, call.Address, call.Result, call.Function, call.Args...)
+	}
+	return nil
+}
+
+var (
+	_ Backend = (*clientBackend)(nil)
+	_ Backend = (*ethclient.Client)(nil)
+)
@@ -0,0 +1,61 @@
+package eth_test
+
+import (
+	"context"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var _ eth.Backend = (*backends.SimulatedBackend)(nil)
+
+func TestAsClientSimulatedBackend(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{from: {Balance: big.NewInt(params.Ether)}}, 8_000_000)
+	defer sim.Close()
+
+	client := eth.AsClient(sim,
+		eth.WithPrivateKey(key),
+		eth.WithChainID(params.AllEthashProtocolChanges.ChainID),
+		eth.WithReceiptPollInterval(10*time.Millisecond),
+	)
+	if eth.AsClient(client.Backend()) != client {
+		t.Fatal("AsClient did not return the client of its Backend")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	txHash, err := client.SendTransaction(ctx, to, big.NewInt(1000), nil)
+	if err != nil {
+		t.Fatalf("SendTransaction: %v", err)
+	}
+	sim.Commit()
+
+	result, err := client.WaitMined(ctx, txHash, 1)
+	if err != nil {
+		t.Fatalf("WaitMined: %v", err)
+	}
+	if result.Reverted {
+		t.Fatal("transaction reverted")
+	}
+
+	balance, err := sim.BalanceAt(ctx, to, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("balance = %s, want 1000", balance)
+	}
+}
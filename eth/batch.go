@@ -0,0 +1,179 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+// DefaultRPCBatchSize là số request tối đa trong một JSON-RPC batch
+const DefaultRPCBatchSize = 100
+
+// ViewBatcher thực hiện nhiều ViewCall trong ít request nhất có thể
+// lỗi trả về là lỗi của cả lần gọi, lỗi của từng lời gọi nằm trong ViewCall.Err
+type ViewBatcher interface {
+	Call(ctx context.Context, calls []*ViewCall) error
+}
+
+// ViewBatcher trả về ViewBatcher các contract wrapper dùng để đọc hàng loạt
+// mặc định dùng Multicall3, client tạo với WithoutMulticall dùng JSON-RPC batch
+// client tạo bằng NewBackendClient không có Multicall3 thì gọi lần lượt từng hàm
+func (c *Client) ViewBatcher() ViewBatcher {
+	if c.multicallAddress == (common.Address{}) {
+		if c.rpc == nil {
+			return &sequentialBatcher{client: c}
+		}
+		return c.RPCBatch()
+	}
+	return c.Multicall()
+}
+
+// BatchCall gửi `elems` bằng JSON-RPC batch, chia thành nhiều batch theo batch size của client
+// lỗi trả về là lỗi của cả batch, lỗi của từng request nằm trong BatchElem.Error
+func (c *Client) BatchCall(ctx context.Context, elems []rpc.BatchElem) error {
+	if c.rpc == nil {
+		return ErrNoRPC
+	}
+
+	batchSize := c.rpcBatchSize
+	if batchSize <= 0 {
+		batchSize = len(elems)
+	}
+
+	for start := 0; start < len(elems); start += batchSize {
+		end := start + batchSize
+		if end > len(elems) {
+			end = len(elems)
+		}
+		batch := elems[start:end]
+		err := c.retry(ctx, func(ctx context.Context) error {
+			return c.RpcClient().BatchCallContext(ctx, batch)
+		})
+		if err != nil {
+			return errors.Wrap(err, "call rpc batch error")
+		}
+	}
+	return nil
+}
+
+// RPCBatch thực hiện các ViewCall bằng JSON-RPC batch `eth_call`, dùng cho các chain không có Multicall3
+type RPCBatch struct {
+	client *Client
+}
+
+// RPCBatch trả về RPCBatch của client
+func (c *Client) RPCBatch() *RPCBatch {
+	return &RPCBatch{client: c}
+}
+
+// Call thực hiện tất cả `calls`, trạng thái được đọc tại block đặt bằng ContextAtBlock
+func (b *RPCBatch) Call(ctx context.Context, calls []*ViewCall) error {
+	block := BlockFromContext(ctx)
+
+	elems := make([]rpc.BatchElem, 0, len(calls))
+	packed := make([]*ViewCall, 0, len(calls))
+	for _, call := range calls {
+		data, err := call.ABI.Pack(call.Function, call.Args...)
+		if err != nil {
+			call.Err = errors.Wrap(err, "rpc batch abi pack error")
+			continue
+		}
+		to := call.Address
+		elems = append(elems, rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []interface{}{toCallArg(ethereum.CallMsg{To: &to, Data: data}), block},
+			Result: new(hexutil.Bytes),
+		})
+		packed = append(packed, call)
+	}
+
+	if err := b.client.BatchCall(ctx, elems); err != nil {
+		return err
+	}
+
+	for i, call := range packed {
+		if elems[i].Error != nil {
+			call.Err = revertError(&call.ABI, elems[i].Error)
+			continue
+		}
+		res := *elems[i].Result.(*hexutil.Bytes)
+		if err := call.ABI.UnpackIntoInterface(call.Result, call.Function, res); err != nil {
+			call.Err = errors.Wrap(err, "unpack result error")
+		}
+	}
+	return nil
+}
+
+// BalancesAt trả về số dư của từng địa chỉ trong `accounts` tại `block` bằng JSON-RPC batch `eth_getBalance`
+// `errs[i]` là lỗi của địa chỉ `accounts[i]`
+func (c *Client) BalancesAt(ctx context.Context, accounts []common.Address, block BlockTag) (balances []*big.Int, errs []error, err error) {
+	elems := make([]rpc.BatchElem, len(accounts))
+	for i, account := range accounts {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getBalance",
+			Args:   []interface{}{account, block},
+			Result: new(hexutil.Big),
+		}
+	}
+
+	if err = c.BatchCall(ctx, elems); err != nil {
+		return nil, nil, err
+	}
+
+	balances = make([]*big.Int, len(accounts))
+	errs = make([]error, len(accounts))
+	for i := range elems {
+		if elems[i].Error != nil {
+			errs[i] = elems[i].Error
+			continue
+		}
+		balances[i] = (*big.Int)(elems[i].Result.(*hexutil.Big))
+	}
+	return balances, errs, nil
+}
+
+// TransactionReceipts trả về receipt của từng transaction trong `txHashes` bằng JSON-RPC batch `eth_getTransactionReceipt`
+// `errs[i]` là lỗi của transaction `txHashes[i]`, ethereum.NotFound nếu transaction chưa được mine
+func (c *Client) TransactionReceipts(ctx context.Context, txHashes []common.Hash) (receipts []*types.Receipt, errs []error, err error) {
+	elems := make([]rpc.BatchElem, len(txHashes))
+	for i, txHash := range txHashes {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{txHash},
+			Result: new(json.RawMessage),
+		}
+	}
+
+	if err = c.BatchCall(ctx, elems); err != nil {
+		return nil, nil, err
+	}
+
+	receipts = make([]*types.Receipt, len(txHashes))
+	errs = make([]error, len(txHashes))
+	for i := range elems {
+		if elems[i].Error != nil {
+			errs[i] = elems[i].Error
+			continue
+		}
+
+		raw := *elems[i].Result.(*json.RawMessage)
+		if len(raw) == 0 || string(raw) == "null" {
+			errs[i] = ethereum.NotFound
+			continue
+		}
+
+		receipt := new(types.Receipt)
+		if err := json.Unmarshal(raw, receipt); err != nil {
+			errs[i] = errors.Wrap(err, "decode receipt error")
+			continue
+		}
+		receipts[i] = receipt
+	}
+	return receipts, errs, nil
+}
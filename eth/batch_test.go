@@ -0,0 +1,121 @@
+package eth_test
+
+import (
+	stderrors "errors"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"testing"
+)
+
+// httpRequests trả về số request HTTP chứa các lời gọi `method`
+func httpRequests(node *ethtest.Node, method string) int {
+	seen := map[int]bool{}
+	for _, request := range node.Requests() {
+		if request.Method == method {
+			seen[request.HTTPRequest] = true
+		}
+	}
+	return len(seen)
+}
+
+func TestRPCBatchViewCalls(t *testing.T) {
+	tests := []struct {
+		name      string
+		batchSize int
+		calls     int
+		reverted  map[int]bool
+		wantHTTP  int
+	}{
+		{name: "single batch", batchSize: 10, calls: 3, wantHTTP: 1},
+		{name: "chunked", batchSize: 2, calls: 5, wantHTTP: 3},
+		{name: "per call revert", batchSize: 10, calls: 3, reverted: map[int]bool{1: true}, wantHTTP: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, node := ethtest.NewClient(t, eth.WithoutMulticall(), eth.WithRPCBatchSize(test.batchSize))
+			accounts := make([]common.Address, test.calls)
+			for i := range accounts {
+				accounts[i] = common.BigToAddress(big.NewInt(int64(0xa0 + i)))
+				if test.reverted[i] {
+					node.OnCallRevert(counterAddress, counterABI, "count", []interface{}{accounts[i]}, "blocked")
+					continue
+				}
+				node.OnCall(counterAddress, counterABI, "count", []interface{}{accounts[i]}, big.NewInt(int64(i)))
+			}
+
+			results := make([]struct{ Count *big.Int }, test.calls)
+			calls := make([]*eth.ViewCall, test.calls)
+			for i, account := range accounts {
+				calls[i] = eth.NewViewCall(counterABI, counterAddress, &results[i], "count", account)
+			}
+			if _, ok := client.ViewBatcher().(*eth.RPCBatch); !ok {
+				t.Fatalf("ViewBatcher = %T, want *eth.RPCBatch", client.ViewBatcher())
+			}
+			if err := client.ViewBatcher().Call(ethtest.Context(t), calls); err != nil {
+				t.Fatalf("ViewBatcher: %v", err)
+			}
+
+			if got := httpRequests(node, "eth_call"); got != test.wantHTTP {
+				t.Fatalf("eth_call sent in %d http requests, want %d", got, test.wantHTTP)
+			}
+			for i, call := range calls {
+				if test.reverted[i] {
+					var revertErr *eth.RevertError
+					if !stderrors.As(call.Err, &revertErr) || revertErr.Reason != "blocked" {
+						t.Fatalf("call %d error = %v, want revert blocked", i, call.Err)
+					}
+					continue
+				}
+				if call.Err != nil {
+					t.Fatalf("call %d error: %v", i, call.Err)
+				}
+				if results[i].Count.Int64() != int64(i) {
+					t.Fatalf("call %d count = %s, want %d", i, results[i].Count, i)
+				}
+			}
+		})
+	}
+}
+
+func TestClientBalancesAt(t *testing.T) {
+	client, node := ethtest.NewClient(t, eth.WithRPCBatchSize(2))
+	node.SetResult("eth_getBalance", (*hexutil.Big)(big.NewInt(500)))
+
+	accounts := []common.Address{alice, bob, counterAddress}
+	balances, errs, err := client.BalancesAt(ethtest.Context(t), accounts, eth.AtBlock(9))
+	if err != nil {
+		t.Fatalf("BalancesAt: %v", err)
+	}
+	for i := range accounts {
+		if errs[i] != nil || balances[i].Int64() != 500 {
+			t.Fatalf("balance %d = %v, %v; want 500", i, balances[i], errs[i])
+		}
+	}
+	if got := httpRequests(node, "eth_getBalance"); got != 2 {
+		t.Fatalf("eth_getBalance sent in %d http requests, want 2", got)
+	}
+}
+
+func TestClientTransactionReceiptsElementError(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	node.SetResult("eth_getTransactionReceipt", nil)
+	node.Inject("eth_getTransactionReceipt", ethtest.Fault{Error: &eth.RPCError{Code: ethtest.CodeServerError, Message: "header not found"}, Times: 1})
+
+	txHashes := []common.Hash{{1}, {2}}
+	receipts, errs, err := client.TransactionReceipts(ethtest.Context(t), txHashes)
+	if err != nil {
+		t.Fatalf("TransactionReceipts: %v", err)
+	}
+	// chỉ lời gọi đầu tiên lỗi, lời gọi thứ hai là transaction chưa được mine
+	if receipts[0] != nil || errs[0] == nil || errs[0] == ethereum.NotFound {
+		t.Fatalf("receipt 0 = %v, %v; want node error", receipts[0], errs[0])
+	}
+	if receipts[1] != nil || errs[1] != ethereum.NotFound {
+		t.Fatalf("receipt 1 = %v, %v; want ethereum.NotFound", receipts[1], errs[1])
+	}
+}
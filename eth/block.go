@@ -0,0 +1,125 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+)
+
+// BlockTag chọn trạng thái của chain khi gọi hàm view: theo số block, theo hash block (EIP-1898)
+// hoặc theo các tag `latest`, `pending`, `earliest`. Giá trị rỗng là `latest`
+type BlockTag struct {
+	tag              string
+	number           *big.Int
+	hash             *common.Hash
+	requireCanonical bool
+}
+
+// LatestBlock là trạng thái của block mới nhất
+func LatestBlock() BlockTag {
+	return BlockTag{tag: "latest"}
+}
+
+// PendingBlock là trạng thái bao gồm các transaction đang chờ trong mempool
+func PendingBlock() BlockTag {
+	return BlockTag{tag: "pending"}
+}
+
+// EarliestBlock là trạng thái của block genesis
+func EarliestBlock() BlockTag {
+	return BlockTag{tag: "earliest"}
+}
+
+// AtBlock là trạng thái tại block số `number`
+func AtBlock(number uint64) BlockTag {
+	return BlockTag{number: new(big.Int).SetUint64(number)}
+}
+
+// AtBlockNumber là trạng thái tại block số `number`, `number` nil là block mới nhất
+func AtBlockNumber(number *big.Int) BlockTag {
+	if number == nil {
+		return LatestBlock()
+	}
+	return BlockTag{number: new(big.Int).Set(number)}
+}
+
+// AtBlockHash là trạng thái tại block có hash `hash` (EIP-1898)
+// nếu `requireCanonical` là true, node trả về lỗi khi block không thuộc chain chính
+func AtBlockHash(hash common.Hash, requireCanonical bool) BlockTag {
+	return BlockTag{hash: &hash, requireCanonical: requireCanonical}
+}
+
+// IsLatest trả về true nếu là block mới nhất
+func (b BlockTag) IsLatest() bool {
+	return b.number == nil && b.hash == nil && (b.tag == "" || b.tag == "latest")
+}
+
+// IsPending trả về true nếu là trạng thái pending
+func (b BlockTag) IsPending() bool {
+	return b.tag == "pending"
+}
+
+// Number trả về số block, nil nếu block được chọn theo tag hoặc hash
+func (b BlockTag) Number() *big.Int {
+	return b.number
+}
+
+// Hash trả về hash block và true nếu block được chọn theo hash
+func (b BlockTag) Hash() (common.Hash, bool) {
+	if b.hash == nil {
+		return common.Hash{}, false
+	}
+	return *b.hash, true
+}
+
+func (b BlockTag) String() string {
+	switch {
+	case b.hash != nil:
+		return b.hash.Hex()
+	case b.number != nil:
+		return b.number.String()
+	case b.tag != "":
+		return b.tag
+	default:
+		return "latest"
+	}
+}
+
+// MarshalJSON mã hoá BlockTag thành tham số block của JSON-RPC
+func (b BlockTag) MarshalJSON() ([]byte, error) {
+	switch {
+	case b.hash != nil:
+		return json.Marshal(map[string]interface{}{
+			"blockHash":        b.hash,
+			"requireCanonical": b.requireCanonical,
+		})
+	case b.number != nil:
+		return json.Marshal((*hexutil.Big)(b.number))
+	case b.tag != "":
+		return json.Marshal(b.tag)
+	default:
+		return json.Marshal("latest")
+	}
+}
+
+type blockTagKey struct{}
+
+// ContextAtBlock trả về context để các hàm view gọi qua CallContractViewFunction,
+// bao gồm tất cả các hàm view của các contract wrapper, đọc trạng thái tại `block`
+//
+//	ctx := eth.ContextAtBlock(ctx, eth.AtBlock(13000000))
+//	balance, err := erc20.BalanceOf(ctx, account)
+func ContextAtBlock(ctx context.Context, block BlockTag) context.Context {
+	return context.WithValue(ctx, blockTagKey{}, block)
+}
+
+// BlockFromContext trả về BlockTag được đặt bằng ContextAtBlock, mặc định là block mới nhất
+func BlockFromContext(ctx context.Context) BlockTag {
+	block, ok := ctx.Value(blockTagKey{}).(BlockTag)
+	if !ok {
+		return LatestBlock()
+	}
+	return block
+}
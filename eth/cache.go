@@ -0,0 +1,413 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// DefaultCacheHeadRefresh là thời gian tối đa ViewCache dùng lại block mới nhất đã đọc trước khi gọi lại `eth_blockNumber`
+const DefaultCacheHeadRefresh = time.Second
+
+// DefaultCachePendingTTL là thời gian tối đa ViewCache chờ một transaction được mine trước khi cho cache lại các kết quả bị ảnh hưởng
+const DefaultCachePendingTTL = 10 * time.Minute
+
+// CachePolicy quyết định kết quả của một hàm view được giữ trong ViewCache bao lâu
+type CachePolicy struct {
+	forever  bool
+	perBlock bool
+	ttl      time.Duration
+}
+
+// CacheForever giữ kết quả cho tới khi bị xoá bởi event hoặc transaction đã đăng ký, dùng cho giá trị không đổi như `name`, `decimals`
+var CacheForever = CachePolicy{forever: true}
+
+// CachePerBlock giữ kết quả cho tới khi có block mới
+var CachePerBlock = CachePolicy{perBlock: true}
+
+// CacheTTL giữ kết quả trong `ttl`
+func CacheTTL(ttl time.Duration) CachePolicy {
+	return CachePolicy{ttl: ttl}
+}
+
+type selector [4]byte
+
+type pendingInvalidation struct {
+	address  common.Address
+	selector selector
+}
+
+// pendingTx là các kết quả bị ảnh hưởng bởi một transaction chưa được mine
+type pendingTx struct {
+	invalidations []pendingInvalidation
+	expires       time.Time
+}
+
+type cacheEntry struct {
+	address  common.Address
+	selector selector
+	data     []byte
+
+	expires time.Time
+	block   uint64
+}
+
+// ViewCache lưu kết quả của CallContractViewFunction theo chính sách của từng hàm view
+// chỉ các hàm đã đặt chính sách và các lời gọi đọc tại block mới nhất được cache
+// một ViewCache chỉ dùng cho các client cùng một chain
+type ViewCache struct {
+	// HeadRefresh là thời gian dùng lại block mới nhất cho chính sách CachePerBlock
+	HeadRefresh time.Duration
+
+	// PendingTTL là thời gian tối đa chờ transaction được mine, sau đó các kết quả bị ảnh hưởng được cache lại
+	// dùng khi transaction bị drop mà không ai gọi WaitMined
+	PendingTTL time.Duration
+
+	mu          sync.Mutex
+	policies    map[selector]CachePolicy
+	eventRules  map[common.Hash][]selector
+	callRules   map[selector][]selector
+	entries     map[string]*cacheEntry
+	pending     map[common.Hash]*pendingTx
+	head        uint64
+	headFetched time.Time
+
+	// version tăng sau mỗi lần xoá kết quả, kết quả đọc trước khi bị xoá không được cache
+	version uint64
+}
+
+// NewViewCache tạo ViewCache rỗng, chưa có hàm nào được cache
+func NewViewCache() *ViewCache {
+	return &ViewCache{
+		HeadRefresh: DefaultCacheHeadRefresh,
+		PendingTTL:  DefaultCachePendingTTL,
+		policies:    make(map[selector]CachePolicy),
+		eventRules:  make(map[common.Hash][]selector),
+		callRules:   make(map[selector][]selector),
+		entries:     make(map[string]*cacheEntry),
+		pending:     make(map[common.Hash]*pendingTx),
+	}
+}
+
+// WithViewCache đặt ViewCache dùng cho CallContractViewFunction, mặc định client không cache
+func WithViewCache(cache *ViewCache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// SetPolicy đặt chính sách cache cho hàm view `function` trong `contractABI`
+// chính sách áp dụng theo selector của hàm nên dùng chung cho mọi contract có cùng hàm
+// panic nếu `contractABI` không có hàm `function`
+func (c *ViewCache) SetPolicy(contractABI abi.ABI, function string, policy CachePolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.policies[methodSelector(contractABI, function)] = policy
+}
+
+// InvalidateOnEvent xoá kết quả của các hàm `functions` của một contract khi thấy event `event` của contract đó
+// event được thấy qua EthGetFilterChanges, EthGetLogs, Watcher, Scanner, Stream, WaitMined hoặc ObserveEvents
+func (c *ViewCache) InvalidateOnEvent(event abi.Event, contractABI abi.ABI, functions ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, function := range functions {
+		c.eventRules[event.ID] = append(c.eventRules[event.ID], methodSelector(contractABI, function))
+	}
+}
+
+// InvalidateOnTransaction xoá kết quả của các hàm `functions` của một contract khi client gửi transaction gọi hàm `method` của contract đó
+// và xoá lại khi WaitMined thấy transaction được mine
+// panic nếu `contractABI` không có hàm `method` hoặc một trong các hàm `functions`
+func (c *ViewCache) InvalidateOnTransaction(contractABI abi.ABI, method string, functions ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	trigger := methodSelector(contractABI, method)
+	for _, function := range functions {
+		c.callRules[trigger] = append(c.callRules[trigger], methodSelector(contractABI, function))
+	}
+}
+
+// ObserveEvents xoá các kết quả bị ảnh hưởng bởi các event trong `changes` theo InvalidateOnEvent
+func (c *ViewCache) ObserveEvents(changes []*FilterChange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, change := range changes {
+		if len(change.Topics) == 0 {
+			continue
+		}
+		for _, function := range c.eventRules[change.EventID()] {
+			c.invalidate(change.Address, function)
+		}
+	}
+}
+
+// Invalidate xoá kết quả của hàm `function` của contract `address`, xoá tất cả các hàm nếu `function` rỗng
+func (c *ViewCache) Invalidate(address common.Address, contractABI abi.ABI, function string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if function == "" {
+		c.version++
+		for key, entry := range c.entries {
+			if entry.address == address {
+				delete(c.entries, key)
+			}
+		}
+		return
+	}
+	c.invalidate(address, methodSelector(contractABI, function))
+}
+
+// Clear xoá toàn bộ kết quả đã cache
+func (c *ViewCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	c.entries = make(map[string]*cacheEntry)
+}
+
+func (c *ViewCache) invalidate(address common.Address, function selector) {
+	c.version++
+	for key, entry := range c.entries {
+		if entry.address == address && entry.selector == function {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// observeTransaction xoá các kết quả bị ảnh hưởng bởi transaction `txHash` với `data` gửi tới `to` theo InvalidateOnTransaction
+// các kết quả này không được cache lại cho tới khi WaitMined thấy transaction được mine hoặc hết PendingTTL
+func (c *ViewCache) observeTransaction(txHash common.Hash, to common.Address, data []byte) {
+	if len(data) < 4 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expirePending()
+	for _, function := range c.callRules[toSelector(data)] {
+		c.invalidate(to, function)
+		tx, ok := c.pending[txHash]
+		if !ok {
+			tx = &pendingTx{expires: time.Now().Add(c.PendingTTL)}
+			c.pending[txHash] = tx
+		}
+		tx.invalidations = append(tx.invalidations, pendingInvalidation{address: to, selector: function})
+	}
+}
+
+// observeReplacement chuyển các kết quả đang chờ transaction `original` sang transaction thay thế `replacement`
+// vì chỉ một trong các transaction cùng nonce được mine
+func (c *ViewCache) observeReplacement(original common.Hash, replacement common.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx, ok := c.pending[original]
+	if !ok {
+		return
+	}
+	delete(c.pending, original)
+
+	tx.expires = time.Now().Add(c.PendingTTL)
+	if other, ok := c.pending[replacement]; ok {
+		tx.invalidations = append(tx.invalidations, other.invalidations...)
+	}
+	c.pending[replacement] = tx
+}
+
+// observeMined xoá lại các kết quả bị ảnh hưởng bởi transaction `txHash` sau khi transaction được mine hoặc bị drop
+func (c *ViewCache) observeMined(txHash common.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx, ok := c.pending[txHash]
+	if !ok {
+		return
+	}
+	for _, pending := range tx.invalidations {
+		c.invalidate(pending.address, pending.selector)
+	}
+	delete(c.pending, txHash)
+}
+
+// expirePending bỏ các transaction đã chờ quá PendingTTL
+func (c *ViewCache) expirePending() {
+	now := time.Now()
+	for txHash, tx := range c.pending {
+		if now.After(tx.expires) {
+			delete(c.pending, txHash)
+		}
+	}
+}
+
+// isPending trả về true nếu kết quả của `function` của `address` đang chờ transaction được mine
+func (c *ViewCache) isPending(address common.Address, function selector) bool {
+	c.expirePending()
+	for _, tx := range c.pending {
+		for _, pending := range tx.invalidations {
+			if pending.address == address && pending.selector == function {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// currentVersion trả về version hiện tại, đọc trước khi gọi node và truyền cho put
+func (c *ViewCache) currentVersion() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.version
+}
+
+// cacheable trả về true nếu hàm được gọi bằng `data` có chính sách cache
+func (c *ViewCache) cacheable(data []byte) bool {
+	if len(data) < 4 {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.policies[toSelector(data)]
+	return ok
+}
+
+// get trả về kết quả còn hiệu lực đã cache của lời gọi `data` tới `address`
+func (c *ViewCache) get(ctx context.Context, client *Client, address common.Address, data []byte) (result []byte, ok bool, err error) {
+	c.mu.Lock()
+	policy := c.policies[toSelector(data)]
+	entry := c.entries[cacheKey(address, data)]
+	c.mu.Unlock()
+	if entry == nil {
+		return nil, false, nil
+	}
+
+	switch {
+	case policy.forever:
+		return entry.data, true, nil
+	case policy.perBlock:
+		head, err := c.headBlock(ctx, client)
+		if err != nil {
+			return nil, false, err
+		}
+		return entry.data, entry.block == head, nil
+	default:
+		return entry.data, time.Now().Before(entry.expires), nil
+	}
+}
+
+// put cache `result` của lời gọi `data` tới `address`, `result` không được cache nếu đã có kết quả bị xoá
+// kể từ khi đọc `version`, vì `result` có thể đã được node trả về trước khi bị xoá
+func (c *ViewCache) put(ctx context.Context, client *Client, address common.Address, data []byte, result []byte, version uint64) error {
+	c.mu.Lock()
+	policy := c.policies[toSelector(data)]
+	c.mu.Unlock()
+
+	entry := &cacheEntry{
+		address:  address,
+		selector: toSelector(data),
+		data:     result,
+	}
+	if policy.perBlock {
+		head, err := c.headBlock(ctx, client)
+		if err != nil {
+			return err
+		}
+		entry.block = head
+	} else if !policy.forever {
+		entry.expires = time.Now().Add(policy.ttl)
+	}
+
+	c.mu.Lock()
+	if c.version == version && !c.isPending(address, entry.selector) {
+		c.entries[cacheKey(address, data)] = entry
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// headBlock trả về block mới nhất, chỉ gọi `eth_blockNumber` khi giá trị đã đọc cũ hơn HeadRefresh
+func (c *ViewCache) headBlock(ctx context.Context, client *Client) (uint64, error) {
+	c.mu.Lock()
+	if time.Since(c.headFetched) < c.HeadRefresh {
+		head := c.head
+		c.mu.Unlock()
+		return head, nil
+	}
+	c.mu.Unlock()
+
+	var head uint64
+	err := client.retry(ctx, func(ctx context.Context) (err error) {
+		head, err = client.blockNumber(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "view cache get block number error")
+	}
+
+	c.mu.Lock()
+	c.head = head
+	c.headFetched = time.Now()
+	c.mu.Unlock()
+	return head, nil
+}
+
+// callContract gọi `eth_call` qua ViewCache của client nếu có
+func (c *Client) callContract(ctx context.Context, address common.Address, data []byte, block BlockTag) ([]byte, error) {
+	callMsg := ethereum.CallMsg{
+		To:   &address,
+		Data: data,
+	}
+	if c.cache == nil || !block.IsLatest() || !c.cache.cacheable(data) {
+		return c.CallContractAt(ctx, callMsg, block)
+	}
+
+	res, ok, err := c.cache.get(ctx, c, address, data)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return res, nil
+	}
+
+	version := c.cache.currentVersion()
+	res, err = c.CallContractAt(ctx, callMsg, block)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.cache.put(ctx, c, address, data, res, version); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func methodSelector(contractABI abi.ABI, function string) selector {
+	method, ok := contractABI.Methods[function]
+	if !ok {
+		panic(fmt.Sprintf("abi has no method %s", function))
+	}
+	return toSelector(method.ID)
+}
+
+func toSelector(data []byte) selector {
+	var s selector
+	copy(s[:], data)
+	return s
+}
+
+func cacheKey(address common.Address, data []byte) string {
+	return string(address.Bytes()) + string(data)
+}
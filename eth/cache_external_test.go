@@ -0,0 +1,43 @@
+package eth_test
+
+import (
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestViewCacheSlowReadRacesInvalidation(t *testing.T) {
+	cache := eth.NewViewCache()
+	cache.SetPolicy(counterABI, "count", eth.CacheForever)
+	client, node := ethtest.NewClient(t, eth.WithViewCache(cache))
+	node.OnCall(counterAddress, counterABI, "count", []interface{}{alice}, big.NewInt(7))
+	node.Inject("eth_call", ethtest.Fault{Delay: 100 * time.Millisecond, Times: 1})
+
+	done := make(chan error)
+	go func() {
+		var result struct {
+			Count *big.Int
+		}
+		done <- client.CallContractViewFunction(ethtest.Context(t), counterABI, counterAddress, &result, "count", alice)
+	}()
+	// xoá kết quả trong lúc lời gọi đầu tiên đang chờ node
+	for node.RequestCount("eth_call") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cache.Invalidate(counterAddress, counterABI, "count")
+	if err := <-done; err != nil {
+		t.Fatalf("slow read: %v", err)
+	}
+
+	// kết quả của lời gọi chậm có thể cũ nên không được cache, lời gọi sau phải tới node
+	count(t, client)
+	if got := node.RequestCount("eth_call"); got != 2 {
+		t.Fatalf("node received %d eth_call, want 2 after the stale write was rejected", got)
+	}
+	count(t, client)
+	if got := node.RequestCount("eth_call"); got != 2 {
+		t.Fatalf("node received %d eth_call, want the fresh result cached", got)
+	}
+}
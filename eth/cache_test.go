@@ -0,0 +1,254 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"strings"
+	"testing"
+	"time"
+)
+
+const storeABIJSON = `[
+	{"type":"function","name":"value","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"setValue","stateMutability":"nonpayable","inputs":[{"name":"value","type":"uint256"}],"outputs":[]},
+	{"type":"event","name":"ValueChanged","inputs":[{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+var (
+	storeABI     = mustParseCacheABI(storeABIJSON)
+	storeAddress = common.HexToAddress("0x00000000000000000000000000000000000000d0")
+	otherStore   = common.HexToAddress("0x00000000000000000000000000000000000000d1")
+)
+
+func mustParseCacheABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// newStoreCache tạo ViewCache cho `value` với chính sách `policy`, xoá khi có event ValueChanged hoặc transaction setValue
+// block mới nhất được đặt sẵn là 5 để chính sách CachePerBlock không cần client
+func newStoreCache(policy CachePolicy) *ViewCache {
+	cache := NewViewCache()
+	cache.HeadRefresh = time.Hour
+	cache.head = 5
+	cache.headFetched = time.Now()
+	cache.SetPolicy(storeABI, "value", policy)
+	cache.InvalidateOnEvent(storeABI.Events["ValueChanged"], storeABI, "value")
+	cache.InvalidateOnTransaction(storeABI, "setValue", "value")
+	return cache
+}
+
+// storeCall trả về data của lời gọi `function` tới store
+func storeCall(function string, args ...interface{}) []byte {
+	data, err := storeABI.Pack(function, args...)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// cached trả về true nếu `cache` có kết quả còn hiệu lực của `value()` tại `address`
+func cached(t *testing.T, cache *ViewCache, address common.Address) bool {
+	t.Helper()
+
+	_, ok, err := cache.get(context.Background(), nil, address, storeCall("value"))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	return ok
+}
+
+// putValue cache kết quả của `value()` tại `address` như một lời gọi đọc `version` trước khi gọi node
+func putValue(t *testing.T, cache *ViewCache, address common.Address, version uint64) {
+	t.Helper()
+
+	if err := cache.put(context.Background(), nil, address, storeCall("value"), []byte{1}, version); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+}
+
+func TestViewCachePolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy CachePolicy
+		// advance chạy sau khi cache kết quả
+		advance func(cache *ViewCache)
+		// wantAfter là true nếu kết quả vẫn còn sau advance
+		wantAfter bool
+	}{
+		{name: "forever", policy: CacheForever, advance: func(cache *ViewCache) { cache.head++ }, wantAfter: true},
+		{name: "per block same block", policy: CachePerBlock, advance: func(cache *ViewCache) {}, wantAfter: true},
+		{name: "per block new block", policy: CachePerBlock, advance: func(cache *ViewCache) { cache.head++ }},
+		{name: "ttl not expired", policy: CacheTTL(time.Hour), advance: func(cache *ViewCache) {}, wantAfter: true},
+		{name: "ttl expired", policy: CacheTTL(10 * time.Millisecond), advance: func(cache *ViewCache) { time.Sleep(20 * time.Millisecond) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := newStoreCache(test.policy)
+			putValue(t, cache, storeAddress, cache.currentVersion())
+			if !cached(t, cache, storeAddress) {
+				t.Fatal("result was not cached")
+			}
+			test.advance(cache)
+			if got := cached(t, cache, storeAddress); got != test.wantAfter {
+				t.Fatalf("cached after advance = %v, want %v", got, test.wantAfter)
+			}
+		})
+	}
+}
+
+func TestViewCachePolicyPerSelector(t *testing.T) {
+	cache := newStoreCache(CacheForever)
+
+	if !cache.cacheable(storeCall("value")) {
+		t.Fatal("value() is not cacheable")
+	}
+	// hàm chưa đặt chính sách không được cache
+	if cache.cacheable(storeCall("name")) || cache.cacheable(nil) {
+		t.Fatal("function without policy is cacheable")
+	}
+	// chính sách theo selector nên áp dụng cho mọi contract có hàm `value`
+	putValue(t, cache, storeAddress, cache.currentVersion())
+	putValue(t, cache, otherStore, cache.currentVersion())
+	if !cached(t, cache, storeAddress) || !cached(t, cache, otherStore) {
+		t.Fatal("value() of both contracts was not cached")
+	}
+	// xoá theo từng contract
+	cache.Invalidate(storeAddress, storeABI, "value")
+	if cached(t, cache, storeAddress) || !cached(t, cache, otherStore) {
+		t.Fatal("Invalidate did not drop only the result of its contract")
+	}
+}
+
+func TestViewCacheRejectsStaleWrite(t *testing.T) {
+	tests := []struct {
+		name string
+		// invalidate chạy trong lúc lời gọi chậm đang chờ node
+		invalidate func(cache *ViewCache)
+		wantCached bool
+	}{
+		{name: "no invalidation", invalidate: func(cache *ViewCache) {}, wantCached: true},
+		{name: "invalidate", invalidate: func(cache *ViewCache) { cache.Invalidate(storeAddress, storeABI, "value") }},
+		{name: "invalidate contract", invalidate: func(cache *ViewCache) { cache.Invalidate(storeAddress, storeABI, "") }},
+		{name: "clear", invalidate: func(cache *ViewCache) { cache.Clear() }},
+		{name: "event", invalidate: func(cache *ViewCache) {
+			cache.ObserveEvents([]*FilterChange{{Address: storeAddress, Topics: []common.Hash{storeABI.Events["ValueChanged"].ID}}})
+		}},
+		{name: "mined transaction", invalidate: func(cache *ViewCache) {
+			cache.observeTransaction(common.Hash{1}, storeAddress, storeCall("setValue", common.Big1))
+			cache.observeMined(common.Hash{1})
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := newStoreCache(CacheForever)
+			version := cache.currentVersion()
+			test.invalidate(cache)
+			putValue(t, cache, storeAddress, version)
+			if got := cached(t, cache, storeAddress); got != test.wantCached {
+				t.Fatalf("cached = %v, want %v", got, test.wantCached)
+			}
+		})
+	}
+}
+
+func TestViewCacheInvalidateOnEvent(t *testing.T) {
+	valueChanged := storeABI.Events["ValueChanged"].ID
+
+	tests := []struct {
+		name        string
+		change      *FilterChange
+		wantDropped bool
+	}{
+		{name: "event of the contract", change: &FilterChange{Address: storeAddress, Topics: []common.Hash{valueChanged}}, wantDropped: true},
+		{name: "event of another contract", change: &FilterChange{Address: otherStore, Topics: []common.Hash{valueChanged}}},
+		{name: "other event", change: &FilterChange{Address: storeAddress, Topics: []common.Hash{{1}}}},
+		{name: "log without topics", change: &FilterChange{Address: storeAddress}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := newStoreCache(CacheForever)
+			putValue(t, cache, storeAddress, cache.currentVersion())
+			cache.ObserveEvents([]*FilterChange{test.change})
+			if dropped := !cached(t, cache, storeAddress); dropped != test.wantDropped {
+				t.Fatalf("dropped = %v, want %v", dropped, test.wantDropped)
+			}
+		})
+	}
+}
+
+func TestViewCacheInvalidateOnTransaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		to          common.Address
+		data        []byte
+		wantDropped bool
+	}{
+		{name: "registered method", to: storeAddress, data: storeCall("setValue", common.Big1), wantDropped: true},
+		{name: "other contract", to: otherStore, data: storeCall("setValue", common.Big1)},
+		{name: "other method", to: storeAddress, data: storeCall("name")},
+		{name: "plain transfer", to: storeAddress},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := newStoreCache(CacheForever)
+			putValue(t, cache, storeAddress, cache.currentVersion())
+			cache.observeTransaction(common.Hash{1}, test.to, test.data)
+			if dropped := !cached(t, cache, storeAddress); dropped != test.wantDropped {
+				t.Fatalf("dropped = %v, want %v", dropped, test.wantDropped)
+			}
+		})
+	}
+}
+
+func TestViewCachePendingTransaction(t *testing.T) {
+	txHash, replacement := common.Hash{1}, common.Hash{2}
+
+	tests := []struct {
+		name string
+		// after chạy sau khi client gửi transaction `txHash`
+		after func(cache *ViewCache)
+		// wantPending là true nếu kết quả đọc trong lúc chờ vẫn không được cache
+		wantPending bool
+	}{
+		{name: "pending", after: func(cache *ViewCache) {}, wantPending: true},
+		{name: "mined", after: func(cache *ViewCache) { cache.observeMined(txHash) }},
+		{name: "replaced", after: func(cache *ViewCache) { cache.observeReplacement(txHash, replacement) }, wantPending: true},
+		{name: "replacement mined", after: func(cache *ViewCache) {
+			cache.observeReplacement(txHash, replacement)
+			cache.observeMined(replacement)
+		}},
+		{name: "original mined after replacement", after: func(cache *ViewCache) {
+			cache.observeReplacement(txHash, replacement)
+			cache.observeMined(txHash)
+		}, wantPending: true},
+		{name: "expired", after: func(cache *ViewCache) { time.Sleep(20 * time.Millisecond) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := newStoreCache(CacheForever)
+			cache.PendingTTL = 10 * time.Millisecond
+			if test.wantPending {
+				cache.PendingTTL = time.Hour
+			}
+			cache.observeTransaction(txHash, storeAddress, storeCall("setValue", common.Big1))
+			test.after(cache)
+
+			// kết quả đọc trong lúc transaction chưa được mine có thể là giá trị cũ nên không được cache
+			putValue(t, cache, storeAddress, cache.currentVersion())
+			if pending := !cached(t, cache, storeAddress); pending != test.wantPending {
+				t.Fatalf("pending = %v, want %v", pending, test.wantPending)
+			}
+		})
+	}
+}
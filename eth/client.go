@@ -2,12 +2,20 @@ package eth
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
 )
 
 // TestnetEndpoint là RPC URL của mạng testnet trên bsc
@@ -17,31 +25,262 @@ const TestnetEndpoint = "https://data-seed-prebsc-1-s1.binance.org:8545/"
 const MainnetEndpoint = "https://bsc-dataseed.binance.org/"
 
 type Client struct {
-	eth *ethclient.Client
-	rpc *rpc.Client
+	eth     *ethclient.Client
+	rpc     *rpc.Client
+	backend Backend
+
+	signer      Signer
+	nonces      *NonceManager
+	gasStrategy GasStrategy
+	maxGasPrice *big.Int
+
+	receiptPollInterval time.Duration
+	replacements        replacements
+
+	multicallAddress common.Address
+	rpcBatchSize     int
+
+	endpoints           *endpointPool
+	maxBlockLag         uint64
+	healthCheckInterval time.Duration
+
+	retryPolicy RetryPolicy
+
+	rateLimit   float64
+	rateBurst   int
+	maxInFlight int
+
+	interceptors []Interceptor
+	tracer       trace.Tracer
+
+	cache       *ViewCache
+	logObserver LogObserver
+
+	mu      sync.Mutex
+	chainID *big.Int
+}
+
+// Option cấu hình thêm cho Client khi khởi tạo
+type Option func(c *Client)
+
+// WithSigner đặt Signer dùng để ký các transaction gửi từ client
+func WithSigner(signer Signer) Option {
+	return func(c *Client) {
+		c.signer = signer
+	}
 }
 
-func NewClient(rpcEndpoint string) (*Client, error) {
-	c := &Client{}
-	var err error
+// WithPrivateKey đặt private key dùng để ký các transaction gửi từ client
+func WithPrivateKey(key *ecdsa.PrivateKey) Option {
+	return WithSigner(NewPrivateKeySigner(key))
+}
+
+// WithNonceManager dùng chung NonceManager `nonces` giữa nhiều client gửi từ cùng một ví
+// mặc định mỗi client tự tạo NonceManager riêng
+func WithNonceManager(nonces *NonceManager) Option {
+	return func(c *Client) {
+		c.nonces = nonces
+	}
+}
+
+// WithGasStrategy đặt GasStrategy quyết định giá gas cho các transaction gửi từ client
+// mặc định client dùng giá gas legacy theo `eth_gasPrice`
+func WithGasStrategy(strategy GasStrategy) Option {
+	return func(c *Client) {
+		c.gasStrategy = strategy
+	}
+}
+
+// WithMaxGasPrice đặt mức trần cho giá mỗi đơn vị gas, transaction vượt mức trần sẽ không được gửi
+func WithMaxGasPrice(maxGasPrice *big.Int) Option {
+	return func(c *Client) {
+		c.maxGasPrice = maxGasPrice
+	}
+}
+
+// WithReceiptPollInterval đặt chu kỳ kiểm tra receipt của WaitMined
+func WithReceiptPollInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		c.receiptPollInterval = interval
+	}
+}
+
+// WithMulticallAddress đặt địa chỉ contract Multicall3 cho các chain không dùng địa chỉ mặc định
+func WithMulticallAddress(address common.Address) Option {
+	return func(c *Client) {
+		c.multicallAddress = address
+	}
+}
+
+// WithoutMulticall tắt Multicall3 cho các chain không có contract này,
+// các hàm đọc hàng loạt của contract wrapper sẽ dùng JSON-RPC batch
+func WithoutMulticall() Option {
+	return WithMulticallAddress(common.Address{})
+}
+
+// WithRPCBatchSize đặt số request tối đa trong một JSON-RPC batch
+func WithRPCBatchSize(batchSize int) Option {
+	return func(c *Client) {
+		c.rpcBatchSize = batchSize
+	}
+}
+
+// WithMaxBlockLag đặt số block tối đa một endpoint của client nhiều endpoint được phép chậm hơn
+// endpoint mới nhất, endpoint chậm hơn sẽ không được dùng
+func WithMaxBlockLag(maxBlockLag uint64) Option {
+	return func(c *Client) {
+		c.maxBlockLag = maxBlockLag
+	}
+}
+
+// WithHealthCheckInterval đặt chu kỳ kiểm tra block mới nhất của các endpoint của client nhiều endpoint
+func WithHealthCheckInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		c.healthCheckInterval = interval
+	}
+}
+
+// WithRetryPolicy đặt chính sách gọi lại các request đọc dữ liệu khi gặp lỗi tạm thời
+// mặc định client không gọi lại (NoRetry)
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimit giới hạn mỗi endpoint nhận tối đa `requestsPerSecond` request mỗi giây, cho phép `burst` request liền nhau
+// giới hạn dùng chung cho mọi contract wrapper giữ cùng client, request vượt giới hạn đợi tới lượt hoặc tới khi context bị huỷ
+// chỉ áp dụng cho endpoint http(s), client tới endpoint websocket hoặc ipc bỏ qua giới hạn này
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.rateLimit = requestsPerSecond
+		c.rateBurst = burst
+	}
+}
+
+// WithMaxInFlight giới hạn mỗi endpoint chỉ có tối đa `maxInFlight` request đang chạy cùng lúc
+// chỉ áp dụng cho endpoint http(s), client tới endpoint websocket hoặc ipc bỏ qua giới hạn này
+func WithMaxInFlight(maxInFlight int) Option {
+	return func(c *Client) {
+		c.maxInFlight = maxInFlight
+	}
+}
+
+func NewClient(rpcEndpoint string, opts ...Option) (*Client, error) {
+	c := newClient(opts)
+
+	// endpoint http(s) đi qua endpointPool để áp dụng giới hạn request và interceptor
+	if u, err := url.Parse(rpcEndpoint); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		pool, err := c.newEndpointPool([]string{rpcEndpoint})
+		if err != nil {
+			return nil, err
+		}
+		rpcClient, err := rpc.DialHTTPWithClient(rpcEndpoint, &http.Client{Transport: c.transport(pool)})
+		if err != nil {
+			return nil, err
+		}
+		c.endpoints = pool
+		return c.init(rpcClient), nil
+	}
 
 	// connect to rpc rpcEndpoint
-	c.eth, err = ethclient.Dial(rpcEndpoint)
+	rpcClient, err := rpc.Dial(rpcEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	return c.init(rpcClient), nil
+}
+
+// NewMultiClient tạo client gửi request tới nhiều endpoint http(s) `rpcEndpoints`
+// mỗi request được gửi tới endpoint tốt nhất theo tỉ lệ lỗi và latency, khi gặp lỗi đường truyền
+// hoặc HTTP 429/5xx thì chuyển sang endpoint tiếp theo. Các endpoint chậm hơn endpoint mới nhất
+// quá số block cho phép (WithMaxBlockLag) không được dùng. Các lời gọi dùng filter id được gửi tới endpoint đã tạo filter,
+// lời gọi tạo filter không chuyển sang endpoint khác khi lỗi để không để lại filter thừa trên node lỗi
+func NewMultiClient(rpcEndpoints []string, opts ...Option) (*Client, error) {
+	c := newClient(opts)
+
+	pool, err := c.newEndpointPool(rpcEndpoints)
 	if err != nil {
 		return nil, err
 	}
 
-	c.rpc, err = rpc.Dial(rpcEndpoint)
+	// url chỉ để tạo rpc client, request thật được endpointPool gửi tới từng endpoint
+	rpcClient, err := rpc.DialHTTPWithClient(rpcEndpoints[0], &http.Client{Transport: c.transport(pool)})
+	if err != nil {
+		return nil, err
+	}
+
+	pool.start(c.healthCheckInterval)
+	c.endpoints = pool
+	return c.init(rpcClient), nil
+}
+
+func newClient(opts []Option) *Client {
+	c := &Client{
+		receiptPollInterval: DefaultReceiptPollInterval,
+		multicallAddress:    Multicall3Address,
+		rpcBatchSize:        DefaultRPCBatchSize,
+		maxBlockLag:         DefaultMaxBlockLag,
+		healthCheckInterval: DefaultHealthCheckInterval,
+		retryPolicy:         NoRetry,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) newEndpointPool(rpcEndpoints []string) (*endpointPool, error) {
+	pool, err := newEndpointPool(rpcEndpoints, http.DefaultTransport, c.maxBlockLag)
 	if err != nil {
 		return nil, err
 	}
-	return c, nil
+	pool.limit(c.rateLimit, c.rateBurst, c.maxInFlight)
+	return pool, nil
+}
+
+// transport trả về http.RoundTripper của rpc client: chuỗi interceptor của client, span JSON-RPC rồi tới `pool`
+func (c *Client) transport(pool *endpointPool) http.RoundTripper {
+	interceptors := c.interceptors
+	if c.tracer != nil {
+		// span JSON-RPC nằm trong cùng để đo request thật sự gửi tới node
+		interceptors = append(interceptors[:len(interceptors):len(interceptors)], c.tracingInterceptor)
+	}
+	return newInterceptorTransport(interceptors, pool)
+}
+
+func (c *Client) init(rpcClient *rpc.Client) *Client {
+	c.rpc = rpcClient
+	c.eth = ethclient.NewClient(rpcClient)
+	c.backend = c.eth
+	return c.initDefaults()
+}
+
+func (c *Client) initDefaults() *Client {
+	if c.nonces == nil {
+		c.nonces = NewNonceManager(c.backend)
+	}
+	if c.gasStrategy == nil {
+		c.gasStrategy = &suggestedGasPrice{multiplier: 1}
+	}
+	return c
 }
 
+// Endpoints trả về tình trạng các endpoint http(s) của client, nil với endpoint websocket hoặc ipc
+// block mới nhất (Head) chỉ được cập nhật với client tạo bằng NewMultiClient
+func (c *Client) Endpoints() []EndpointStatus {
+	if c.endpoints == nil {
+		return nil
+	}
+	return c.endpoints.statuses()
+}
+
+// EthClient trả về ethclient của client, nil với client tạo bằng NewBackendClient
 func (c *Client) EthClient() *ethclient.Client {
 	return c.eth
 }
 
+// RpcClient trả về rpc client của client, nil với client tạo bằng NewBackendClient
 func (c *Client) RpcClient() *rpc.Client {
 	return c.rpc
 }
@@ -50,20 +289,28 @@ func (c *Client) RpcClient() *rpc.Client {
 // `args` là params truyền vào hàm abi.Value
 // giá trị trả về sẽ được unpack vào `result`
 // `result` phải là pointer
+// trạng thái được đọc tại block đặt bằng ContextAtBlock, mặc định là block mới nhất
+// nếu contract bị revert, lỗi trả về chứa RevertError, lấy ra bằng errors.As
 func (c *Client) CallContractViewFunction(ctx context.Context, abi abi.ABI, contractAddress common.Address, result interface{}, function string, args ...interface{}) error {
+	return c.CallContractViewFunctionAt(ctx, BlockFromContext(ctx), abi, contractAddress, result, function, args...)
+}
+
+// CallContractViewFunctionAt giống CallContractViewFunction nhưng đọc trạng thái tại `block`
+func (c *Client) CallContractViewFunctionAt(ctx context.Context, block BlockTag, abi abi.ABI, contractAddress common.Address, result interface{}, function string, args ...interface{}) error {
+	err := c.callContractViewFunction(ctx, block, abi, contractAddress, result, function, args...)
+	recordSpanError(ctx, err)
+	return err
+}
+
+func (c *Client) callContractViewFunction(ctx context.Context, block BlockTag, abi abi.ABI, contractAddress common.Address, result interface{}, function string, args ...interface{}) error {
 	data, err := abi.Pack(function, args...)
 	if err != nil {
 		return errors.Wrap(err, "client abi pack error")
 	}
 
-	callMsg := ethereum.CallMsg{
-		To:   &contractAddress,
-		Data: data,
-	}
-
-	res, err := c.eth.CallContract(ctx, callMsg, nil)
+	res, err := c.callContract(ctx, contractAddress, data, block)
 	if err != nil {
-		return errors.Wrap(err, "client call contract error")
+		return errors.Wrap(revertError(&abi, err), "client call contract error")
 	}
 
 	err = abi.UnpackIntoInterface(result, function, res)
@@ -74,7 +321,47 @@ func (c *Client) CallContractViewFunction(ctx context.Context, abi abi.ABI, cont
 	return nil
 }
 
+// CallContractAt gọi `eth_call` với `msg` tại `block`
+func (c *Client) CallContractAt(ctx context.Context, msg ethereum.CallMsg, block BlockTag) ([]byte, error) {
+	if c.rpc == nil {
+		return c.callBackend(ctx, msg, block)
+	}
+
+	var hex hexutil.Bytes
+	err := c.retry(ctx, func(ctx context.Context) error {
+		return c.RpcClient().CallContext(ctx, &hex, "eth_call", toCallArg(msg), block)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hex, nil
+}
+
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	return arg
+}
+
 func (c *Client) Close() {
-	c.eth.Close()
-	c.rpc.Close()
+	if c.rpc != nil {
+		c.rpc.Close()
+	}
+	if c.endpoints != nil {
+		c.endpoints.close()
+	}
 }
@@ -2,80 +2,154 @@ package eth
 
 import (
 	"context"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/pkg/errors"
-	"strconv"
+	"strings"
 )
 
+// ErrFilterNotRemoved được trả về khi `eth_uninstallFilter` trả về false, thường do filter đã hết hạn
+var ErrFilterNotRemoved = errors.New("remove filter failed")
+
+// IsFilterNotFoundError trả về true nếu `err` là lỗi node đã xoá filter, do filter lâu không được đọc hoặc node khởi động lại
+func IsFilterNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+	rpcErr, ok := errors.Cause(err).(rpc.Error)
+	return ok && strings.Contains(strings.ToLower(rpcErr.Error()), "filter not found")
+}
+
+// EthNewFilter tạo filter với khoảng block `fromBlock`, `toBlock` là số thập phân, hex hoặc tag
+// xem EthNewFilterQuery
 func (c *Client) EthNewFilter(ctx context.Context, fromBlock string, toBlock string, addresses []common.Address, topics [][]common.Hash) (string, error) {
-	var filterID string
+	query := FilterQuery{Addresses: addresses, Topics: topics}
+	var err error
+	if query.FromBlock, err = parseFilterBlock(fromBlock); err != nil {
+		return "", err
+	}
+	if query.ToBlock, err = parseFilterBlock(toBlock); err != nil {
+		return "", err
+	}
+	return c.EthNewFilterQuery(ctx, query)
+}
+
+// EthNewFilterQuery tạo filter trên node bằng `eth_newFilter`, đọc log mới bằng EthGetFilterChanges
+// lời gọi không được retry vì request lỗi vẫn có thể đã tạo filter trên node
+func (c *Client) EthNewFilterQuery(ctx context.Context, query FilterQuery) (string, error) {
+	if c.rpc == nil {
+		return "", ErrNoRPC
+	}
 
-	err := c.RpcClient().CallContext(ctx, &filterID, "eth_newFilter", toNewFilterArg(fromBlock, toBlock, addresses, topics))
+	var filterID string
+	err := c.RpcClient().CallContext(ctx, &filterID, "eth_newFilter", query)
 	if err != nil {
 		return "", errors.Wrap(err, "call rpc error")
 	}
 	return filterID, nil
 }
 
-func (c *Client) EthGetFilterChanges(ctx context.Context, filterID string) ([]*FilterChange, error) {
-	var changes []*FilterChange
+// EthGetLogs trả về các log khớp `query` bằng `eth_getLogs`
+// client tạo bằng NewBackendClient dùng FilterLogs của Backend
+func (c *Client) EthGetLogs(ctx context.Context, query FilterQuery) ([]*FilterChange, error) {
+	if c.rpc == nil {
+		q, err := c.backendQuery(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		logs, err := c.backend.FilterLogs(ctx, q)
+		if err != nil {
+			return nil, errors.Wrap(err, "filter logs error")
+		}
+		changes := make([]*FilterChange, len(logs))
+		for i := range logs {
+			changes[i] = NewFilterChange(&logs[i])
+		}
+		c.observeEvents(changes)
+		return changes, nil
+	}
 
-	err := c.RpcClient().CallContext(ctx, &changes, "eth_getFilterChanges", filterID)
+	var changes []*FilterChange
+	err := c.retry(ctx, func(ctx context.Context) error {
+		return c.RpcClient().CallContext(ctx, &changes, "eth_getLogs", query)
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "call rpc error")
+		return nil, errors.Wrap(err, "call get logs error")
 	}
+	c.observeEvents(changes)
 	return changes, nil
 }
 
-func (c *Client) EthUninstallFilter(ctx context.Context, filterID string) error {
-	var removed bool
-	err := c.RpcClient().CallContext(ctx, &removed, "eth_uninstallFilter", filterID)
+// EthSubscribeLogs đăng ký nhận log mới khớp `query` qua subscription `logs`, chỉ dùng được với endpoint websocket
+// client tạo bằng NewBackendClient dùng SubscribeFilterLogs của Backend
+func (c *Client) EthSubscribeLogs(ctx context.Context, query FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	if c.rpc == nil {
+		q, err := c.backendQuery(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return c.backend.SubscribeFilterLogs(ctx, q, ch)
+	}
+
+	sub, err := c.RpcClient().EthSubscribe(ctx, ch, "logs", query)
 	if err != nil {
-		return errors.Wrap(err, "call uninstall filter error")
+		return nil, errors.Wrap(err, "subscribe logs error")
 	}
-	if !removed {
-		return errors.New("remove filter failed")
+	return sub, nil
+}
+
+// backendQuery chuyển `query` thành ethereum.FilterQuery cho Backend, chỉ đọc block mới nhất khi FromBlock là block mới nhất
+func (c *Client) backendQuery(ctx context.Context, query FilterQuery) (ethereum.FilterQuery, error) {
+	var head uint64
+	if query.BlockHash == nil && filterBlockNumber(query.FromBlock) == nil {
+		var err error
+		if head, err = c.blockNumber(ctx); err != nil {
+			return ethereum.FilterQuery{}, errors.Wrap(err, "get block number error")
+		}
 	}
-	return nil
+	return query.Ethereum(head), nil
 }
 
-func toNewFilterArg(fromBlock string, toBlock string, addresses []common.Address, topics [][]common.Hash) interface{} {
-	arg := map[string]interface{}{}
-	fromBlockID, err := strconv.ParseInt(fromBlock, 10, 64)
-	if err == nil {
-		arg["fromBlock"] = fromBlockID
-	} else {
-		arg["fromBlock"] = fromBlock
+// EthGetFilterChanges trả về các log mới của filter `filterID` từ lần đọc trước
+// lời gọi không được retry vì node đã bỏ các log trả về trong response bị lỗi, dùng Watcher để tạo lại filter và đọc bù
+func (c *Client) EthGetFilterChanges(ctx context.Context, filterID string) ([]*FilterChange, error) {
+	if c.rpc == nil {
+		return nil, ErrNoRPC
 	}
 
-	toBlockID, err := strconv.ParseInt(toBlock, 10, 64)
+	var changes []*FilterChange
+	err := c.RpcClient().CallContext(ctx, &changes, "eth_getFilterChanges", filterID)
 	if err != nil {
-		arg["toBlock"] = toBlock
-	} else {
-		arg["toBlock"] = toBlockID
+		return nil, errors.Wrap(err, "call rpc error")
 	}
+	c.observeEvents(changes)
+	return changes, nil
+}
 
-	if addresses != nil && len(addresses) > 0 {
-		if len(addresses) == 1 {
-			arg["address"] = addresses[0]
-		} else {
-			arg["address"] = addresses
-		}
+// observeEvents báo `changes` cho ViewCache nếu có để xoá các kết quả bị ảnh hưởng
+// log nhận qua subscription không đi qua client nên Stream tự báo
+func (c *Client) observeEvents(changes []*FilterChange) {
+	if c.cache != nil {
+		c.cache.ObserveEvents(changes)
 	}
+}
 
-	if topics != nil && len(topics) > 0 {
-		argTopics := make([]interface{}, 0)
-		for i := 0; i < len(topics); i++ {
-			if topics[i] == nil || len(topics[i]) == 0 {
-				argTopics = append(argTopics, "null")
-			} else if len(topics[i]) == 1 {
-				argTopics = append(argTopics, topics[i][0])
-			} else {
-				argTopics = append(argTopics, topics[i])
-			}
-		}
-		arg["topics"] = argTopics
+func (c *Client) EthUninstallFilter(ctx context.Context, filterID string) error {
+	if c.rpc == nil {
+		return ErrNoRPC
 	}
 
-	return arg
+	var removed bool
+	err := c.retry(ctx, func(ctx context.Context) error {
+		return c.RpcClient().CallContext(ctx, &removed, "eth_uninstallFilter", filterID)
+	})
+	if err != nil {
+		return errors.Wrap(err, "call uninstall filter error")
+	}
+	if !removed {
+		return ErrFilterNotRemoved
+	}
+	return nil
 }
@@ -0,0 +1,142 @@
+package eth_test
+
+import (
+	"context"
+	stderrors "errors"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+const counterABIJSON = `[
+	{"type":"function","name":"count","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"count","type":"uint256"}]},
+	{"type":"event","name":"Counted","inputs":[{"name":"account","type":"address","indexed":true},{"name":"count","type":"uint256","indexed":false}]}
+]`
+
+var (
+	counterABI     = mustParseABI(counterABIJSON)
+	counterAddress = common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	alice          = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+)
+
+func mustParseABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func TestClientChainID(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	node.SetChainID(big.NewInt(56))
+
+	chainID, err := client.ChainID(ethtest.Context(t))
+	if err != nil {
+		t.Fatalf("ChainID: %v", err)
+	}
+	if chainID.Int64() != 56 {
+		t.Fatalf("ChainID = %s, want 56", chainID)
+	}
+}
+
+func TestClientCallContractViewFunction(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	node.OnCall(counterAddress, counterABI, "count", []interface{}{alice}, big.NewInt(7))
+
+	var result struct {
+		Count *big.Int
+	}
+	err := client.CallContractViewFunction(ethtest.Context(t), counterABI, counterAddress, &result, "count", alice)
+	if err != nil {
+		t.Fatalf("CallContractViewFunction: %v", err)
+	}
+	if result.Count.Int64() != 7 {
+		t.Fatalf("count = %s, want 7", result.Count)
+	}
+}
+
+func TestClientCallContractViewFunctionRevert(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	node.OnCallRevert(counterAddress, counterABI, "count", []interface{}{alice}, "account is blocked")
+
+	var result struct {
+		Count *big.Int
+	}
+	err := client.CallContractViewFunction(ethtest.Context(t), counterABI, counterAddress, &result, "count", alice)
+
+	var revertErr *eth.RevertError
+	if !stderrors.As(err, &revertErr) {
+		t.Fatalf("error = %v, want RevertError", err)
+	}
+	if revertErr.Reason != "account is blocked" {
+		t.Fatalf("revert reason = %q, want %q", revertErr.Reason, "account is blocked")
+	}
+}
+
+func TestClientEthGetLogs(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	node.AddEvent(1, counterAddress, counterABI, "Counted", alice, big.NewInt(1))
+	node.AddEvent(2, common.HexToAddress("0xff"), counterABI, "Counted", alice, big.NewInt(2))
+	node.AddEvent(3, counterAddress, counterABI, "Counted", alice, big.NewInt(3))
+
+	changes, err := client.EthGetLogs(ethtest.Context(t), eth.FilterQuery{
+		FromBlock: eth.AtBlock(0),
+		ToBlock:   eth.AtBlock(3),
+		Addresses: []common.Address{counterAddress},
+	})
+	if err != nil {
+		t.Fatalf("EthGetLogs: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("got %d logs, want 2", len(changes))
+	}
+	for i, want := range []int64{1, 3} {
+		if number, _ := changes[i].ParseBlockNumber(); int64(number) != want {
+			t.Fatalf("log %d at block %d, want %d", i, number, want)
+		}
+	}
+}
+
+func TestStreamDeliversBlockMinedWhileInstallingFilter(t *testing.T) {
+	var node *ethtest.Node
+	minedBlock := eth.Interceptor(func(ctx context.Context, call *eth.RPCCall, invoker eth.Invoker) error {
+		// block 1 được tạo ngay trước khi node nhận `eth_newFilter` nên filter không thấy log của block này
+		if call.Method == "eth_newFilter" && node.Head() == 0 {
+			node.AddEvent(1, counterAddress, counterABI, "Counted", alice, big.NewInt(1))
+		}
+		return invoker(ctx, call)
+	})
+	client, node := ethtest.NewClient(t, eth.WithInterceptors(minedBlock))
+
+	ctx, cancel := context.WithCancel(ethtest.Context(t))
+	defer cancel()
+
+	stream := client.NewStream(eth.FilterQuery{Addresses: []common.Address{counterAddress}}, 1)
+	stream.Interval = time.Millisecond
+
+	var delivered []uint64
+	err := stream.Run(ctx, func(ctx context.Context, changes []*eth.FilterChange) error {
+		for _, change := range changes {
+			number, _ := change.ParseBlockNumber()
+			delivered = append(delivered, number)
+		}
+		if len(delivered) == 1 {
+			node.AddEvent(2, counterAddress, counterABI, "Counted", alice, big.NewInt(2))
+		} else {
+			cancel()
+		}
+		return nil
+	})
+	if !stderrors.Is(err, context.Canceled) {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(delivered) != 2 || delivered[0] != 1 || delivered[1] != 2 {
+		t.Fatalf("delivered blocks %v, want [1 2]", delivered)
+	}
+}
@@ -0,0 +1,248 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+// ErrNoSigner được trả về khi gửi transaction từ client không có Signer
+var ErrNoSigner = errors.New("client has no signer")
+
+// From trả về địa chỉ ví dùng để gửi transaction của client
+func (c *Client) From() (common.Address, error) {
+	if c.signer == nil {
+		return common.Address{}, ErrNoSigner
+	}
+	return c.signer.Address(), nil
+}
+
+// SendContractTransaction gửi transaction gọi hàm `function` của contract với abi là `abi`
+// `args` là params truyền vào hàm
+// gas được estimate trước khi ký và gửi, giá trị trả về là hash của transaction
+// nếu contract revert khi estimate gas, lỗi trả về chứa RevertError, lấy ra bằng errors.As
+func (c *Client) SendContractTransaction(ctx context.Context, abi abi.ABI, contractAddress common.Address, function string, args ...interface{}) (common.Hash, error) {
+	data, err := abi.Pack(function, args...)
+	if err != nil {
+		err = errors.Wrap(err, "client abi pack error")
+		recordSpanError(ctx, err)
+		return common.Hash{}, err
+	}
+
+	txHash, err := c.sendTransaction(ctx, &abi, contractAddress, nil, data)
+	recordSpanError(ctx, err)
+	return txHash, err
+}
+
+// maxNonceRetries là số lần gửi lại transaction với nonce mới khi nonce đã bị dùng
+const maxNonceRetries = 3
+
+// SendTransaction ký và gửi transaction tới địa chỉ `to` với `value` wei và `data`
+// nonce được cấp bởi NonceManager của client, nếu node báo nonce đã bị dùng
+// thì nonce được đồng bộ lại và transaction được gửi lại
+func (c *Client) SendTransaction(ctx context.Context, to common.Address, value *big.Int, data []byte) (common.Hash, error) {
+	txHash, err := c.sendTransaction(ctx, nil, to, value, data)
+	recordSpanError(ctx, err)
+	return txHash, err
+}
+
+// sendTransaction dùng `contractABI` để decode custom error khi estimate gas bị revert
+func (c *Client) sendTransaction(ctx context.Context, contractABI *abi.ABI, to common.Address, value *big.Int, data []byte) (common.Hash, error) {
+	from, err := c.From()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	fees, err := c.gasFees(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	gas, err := c.backend.EstimateGas(ctx, ethereum.CallMsg{
+		From:     from,
+		To:       &to,
+		GasPrice: fees.GasPrice,
+		Value:    value,
+		Data:     data,
+	})
+	if err != nil {
+		return common.Hash{}, errors.Wrap(revertError(contractABI, err), "client estimate gas error")
+	}
+
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		nonce, err := c.nonces.Next(ctx, from)
+		if err != nil {
+			return common.Hash{}, errors.Wrap(err, "client get nonce error")
+		}
+
+		tx := newTx(chainID, nonce, to, value, gas, fees, data)
+		txHash, err := c.signAndSend(ctx, from, tx)
+		if err == nil {
+			if c.cache != nil {
+				c.cache.observeTransaction(txHash, to, data)
+			}
+			return txHash, nil
+		}
+		if !IsNonceError(err) || attempt >= maxNonceRetries {
+			return common.Hash{}, err
+		}
+
+		if err = c.nonces.Resync(ctx, from); err != nil {
+			return common.Hash{}, err
+		}
+	}
+}
+
+// signAndSend ký và gửi `tx` với nonce đã được cấp bởi NonceManager
+// nonce được trả lại cho NonceManager nếu transaction chắc chắn không tới được mempool
+func (c *Client) signAndSend(ctx context.Context, from common.Address, tx *types.Transaction) (common.Hash, error) {
+	signedTx, err := c.signAndSendTx(ctx, tx)
+	if err != nil {
+		// node đã từ chối transaction hoặc transaction chưa được gửi thì nonce chưa được dùng,
+		// lỗi đường truyền thì transaction có thể đã tới node nên vẫn giữ lại để kiểm tra bằng Gaps
+		if signedTx == nil || isRejectedError(err) {
+			c.nonces.Release(from, tx.Nonce())
+		} else {
+			c.nonces.Sent(from, tx.Nonce(), signedTx.Hash())
+		}
+		return common.Hash{}, err
+	}
+
+	c.nonces.Sent(from, tx.Nonce(), signedTx.Hash())
+	return signedTx.Hash(), nil
+}
+
+// signAndSendTx ký và gửi `tx`, không quản lý nonce. Khi gửi lỗi, transaction đã ký vẫn được trả về cùng lỗi
+// transaction đã nằm trong mempool của node từ lần gửi trước không bị coi là lỗi
+func (c *Client) signAndSendTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx, err := c.signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return nil, errors.Wrap(err, "client sign transaction error")
+	}
+
+	err = c.backend.SendTransaction(ctx, signedTx)
+	if IsKnownTransactionError(err) {
+		// transaction này đã tới node trong lần gửi trước, không phải lỗi
+		err = nil
+	}
+	if err != nil {
+		return signedTx, errors.Wrap(err, "client send transaction error")
+	}
+	return signedTx, nil
+}
+
+// isRejectedError trả về true nếu node đã nhận request và từ chối transaction
+func isRejectedError(err error) bool {
+	_, ok := errors.Cause(err).(rpc.Error)
+	return ok
+}
+
+// newTx tạo transaction legacy hoặc EIP-1559 tuỳ theo `fees`
+func newTx(chainID *big.Int, nonce uint64, to common.Address, value *big.Int, gas uint64, fees *GasFees, data []byte) *types.Transaction {
+	if fees.IsLegacy() {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: fees.GasPrice,
+			Gas:      gas,
+			To:       &to,
+			Value:    value,
+			Data:     data,
+		})
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: fees.GasTipCap,
+		GasFeeCap: fees.GasFeeCap,
+		Gas:       gas,
+		To:        &to,
+		Value:     value,
+		Data:      data,
+	})
+}
+
+// NonceManager trả về NonceManager cấp nonce cho các transaction của client
+func (c *Client) NonceManager() *NonceManager {
+	return c.nonces
+}
+
+// FillNonceGaps lấp các khoảng trống nonce của ví gửi bằng transaction chuyển 0 wei cho chính nó
+// để các transaction phía sau không bị kẹt, giá trị trả về là hash của các transaction đã gửi
+func (c *Client) FillNonceGaps(ctx context.Context) ([]common.Hash, error) {
+	from, err := c.From()
+	if err != nil {
+		return nil, err
+	}
+
+	gaps, err := c.nonces.Gaps(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := c.gasFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txHashes := make([]common.Hash, 0, len(gaps))
+	for _, nonce := range gaps {
+		if !c.nonces.claim(from, nonce) {
+			continue
+		}
+
+		tx := newTx(chainID, nonce, from, new(big.Int), params.TxGas, fees, nil)
+		txHash, err := c.signAndSend(ctx, from, tx)
+		if err != nil {
+			return txHashes, errors.Wrapf(err, "client fill nonce gap %d error", nonce)
+		}
+		txHashes = append(txHashes, txHash)
+	}
+	return txHashes, nil
+}
+
+// ChainID trả về chain id của mạng, giá trị được cache sau lần gọi đầu tiên
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.chainID != nil {
+		return c.chainID, nil
+	}
+
+	backend, ok := c.backend.(interface {
+		ChainID(ctx context.Context) (*big.Int, error)
+	})
+	if !ok {
+		return nil, errors.New("backend does not provide chain id, create client with WithChainID")
+	}
+	chainID, err := backend.ChainID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "client get chain id error")
+	}
+	c.chainID = chainID
+	return chainID, nil
+}
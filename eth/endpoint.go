@@ -0,0 +1,456 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBlockLag là số block tối đa một endpoint được phép chậm hơn endpoint mới nhất
+const DefaultMaxBlockLag = 5
+
+// DefaultHealthCheckInterval là chu kỳ mặc định kiểm tra block mới nhất của các endpoint
+const DefaultHealthCheckInterval = 10 * time.Second
+
+// healthCheckTimeout là thời gian tối đa của một lần kiểm tra endpoint
+const healthCheckTimeout = 5 * time.Second
+
+// ewmaWeight là trọng số của giá trị mới khi tính trung bình latency và tỉ lệ lỗi
+const ewmaWeight = 0.2
+
+// EndpointStatus là tình trạng của một endpoint trong client nhiều endpoint
+type EndpointStatus struct {
+	URL       string
+	Head      uint64
+	Latency   time.Duration
+	ErrorRate float64
+	Requests  uint64
+	Failures  uint64
+	LastError error
+
+	// Lagging là true nếu endpoint chậm hơn endpoint mới nhất quá số block cho phép
+	Lagging bool
+}
+
+type endpoint struct {
+	url     *url.URL
+	health  *rpc.Client
+	limiter *rateLimiter
+
+	mu        sync.Mutex
+	head      uint64
+	latency   time.Duration
+	errorRate float64
+	requests  uint64
+	failures  uint64
+	lastErr   error
+}
+
+func (e *endpoint) success(latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+	e.errorRate = e.errorRate * (1 - ewmaWeight)
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(float64(e.latency)*(1-ewmaWeight) + float64(latency)*ewmaWeight)
+	}
+}
+
+func (e *endpoint) failure(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+	e.failures++
+	e.errorRate = e.errorRate*(1-ewmaWeight) + ewmaWeight
+	e.lastErr = err
+}
+
+func (e *endpoint) status() EndpointStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return EndpointStatus{
+		URL:       e.url.String(),
+		Head:      e.head,
+		Latency:   e.latency,
+		ErrorRate: e.errorRate,
+		Requests:  e.requests,
+		Failures:  e.failures,
+		LastError: e.lastErr,
+	}
+}
+
+// endpointPool là http.RoundTripper gửi mỗi request tới endpoint tốt nhất
+// và chuyển sang endpoint tiếp theo khi gặp lỗi đường truyền
+// filter chỉ tồn tại trên node đã tạo nó nên các lời gọi dùng filter id luôn được gửi tới endpoint đã tạo filter,
+// request tạo filter không được chuyển sang endpoint khác vì node lỗi vẫn có thể đã tạo filter mà không ai xoá
+// người gọi tạo lại filter khi lỗi, ví dụ Watcher tạo lại filter ở lần Poll sau
+type endpointPool struct {
+	endpoints   []*endpoint
+	transport   http.RoundTripper
+	maxBlockLag uint64
+
+	filtersMu sync.Mutex
+	filters   map[string]*endpoint
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newFilterMethods là các hàm tạo filter trên node, kết quả là filter id
+var newFilterMethods = map[string]bool{
+	"eth_newFilter":                   true,
+	"eth_newBlockFilter":              true,
+	"eth_newPendingTransactionFilter": true,
+}
+
+// filterMethods là các hàm có params đầu tiên là filter id
+var filterMethods = map[string]bool{
+	"eth_getFilterChanges": true,
+	"eth_getFilterLogs":    true,
+	"eth_uninstallFilter":  true,
+}
+
+func newEndpointPool(rawURLs []string, transport http.RoundTripper, maxBlockLag uint64) (*endpointPool, error) {
+	if len(rawURLs) == 0 {
+		return nil, errors.New("no rpc endpoint")
+	}
+
+	p := &endpointPool{
+		transport:   transport,
+		maxBlockLag: maxBlockLag,
+		filters:     make(map[string]*endpoint),
+		quit:        make(chan struct{}),
+	}
+	for _, rawURL := range rawURLs {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid rpc endpoint %s", rawURL)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, errors.Errorf("rpc endpoint %s is not http or https", rawURL)
+		}
+
+		health, err := rpc.DialHTTPWithClient(rawURL, &http.Client{Transport: transport})
+		if err != nil {
+			return nil, errors.Wrapf(err, "dial rpc endpoint %s error", rawURL)
+		}
+		p.endpoints = append(p.endpoints, &endpoint{url: u, health: health})
+	}
+	return p, nil
+}
+
+// limit đặt giới hạn request mỗi giây và số request cùng lúc cho từng endpoint
+// giới hạn áp dụng cho cả request của người gọi và request kiểm tra endpoint
+func (p *endpointPool) limit(rate float64, burst int, maxInFlight int) {
+	for _, e := range p.endpoints {
+		e.limiter = newRateLimiter(rate, burst, maxInFlight)
+	}
+}
+
+// start kiểm tra các endpoint một lần rồi kiểm tra định kỳ sau mỗi `interval`
+func (p *endpointPool) start(interval time.Duration) {
+	p.checkHealth()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.quit:
+				return
+			case <-ticker.C:
+				p.checkHealth()
+			}
+		}
+	}()
+}
+
+func (p *endpointPool) close() {
+	close(p.quit)
+	p.wg.Wait()
+	for _, e := range p.endpoints {
+		e.health.Close()
+	}
+}
+
+func (p *endpointPool) checkHealth() {
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+			defer cancel()
+
+			release, err := e.limiter.acquire(ctx)
+			if err != nil {
+				return
+			}
+			defer release()
+
+			var head hexutil.Uint64
+			start := time.Now()
+			if err := e.health.CallContext(ctx, &head, "eth_blockNumber"); err != nil {
+				e.failure(errors.Wrap(err, "health check error"))
+				return
+			}
+			e.success(time.Since(start))
+
+			e.mu.Lock()
+			e.head = uint64(head)
+			e.mu.Unlock()
+		}(e)
+	}
+	wg.Wait()
+}
+
+func (p *endpointPool) statuses() []EndpointStatus {
+	statuses := make([]EndpointStatus, len(p.endpoints))
+	var best uint64
+	for i, e := range p.endpoints {
+		statuses[i] = e.status()
+		if statuses[i].Head > best {
+			best = statuses[i].Head
+		}
+	}
+	for i := range statuses {
+		statuses[i].Lagging = statuses[i].Head+p.maxBlockLag < best
+	}
+	return statuses
+}
+
+// ranked trả về các endpoint theo thứ tự ưu tiên: ít lỗi hơn rồi nhanh hơn
+// các endpoint chậm quá nhiều block bị loại, trừ khi tất cả đều chậm
+func (p *endpointPool) ranked() []*endpoint {
+	statuses := p.statuses()
+
+	candidates := make([]int, 0, len(p.endpoints))
+	for i := range p.endpoints {
+		if !statuses[i].Lagging {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		for i := range p.endpoints {
+			candidates = append(candidates, i)
+		}
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		sa, sb := statuses[candidates[a]], statuses[candidates[b]]
+		if sa.ErrorRate != sb.ErrorRate {
+			return sa.ErrorRate < sb.ErrorRate
+		}
+		return sa.Latency < sb.Latency
+	})
+
+	ranked := make([]*endpoint, len(candidates))
+	for i, index := range candidates {
+		ranked[i] = p.endpoints[index]
+	}
+	return ranked
+}
+
+func (p *endpointPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "read request body error")
+		}
+	}
+
+	var msgs []*jsonrpcMessage
+	ranked := p.ranked()
+	if len(p.endpoints) > 1 {
+		msgs = decodeMessages(body)
+		pinned, err := p.filterEndpoint(msgs)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case pinned != nil:
+			// node khác không có filter nên không chuyển sang endpoint khác khi lỗi
+			ranked = []*endpoint{pinned}
+		case createsFilter(msgs):
+			ranked = ranked[:1]
+		}
+	}
+
+	var lastErr error
+	for i, e := range ranked {
+		endpointURL := *e.url
+		endpointReq := req.Clone(req.Context())
+		endpointReq.URL = &endpointURL
+		endpointReq.Host = e.url.Host
+		endpointReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+		endpointReq.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+
+		release, err := e.limiter.acquire(req.Context())
+		if err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := p.transport.RoundTrip(endpointReq)
+		if err == nil && !isFailoverStatus(resp.StatusCode) {
+			e.success(time.Since(start))
+			if err = p.trackFilters(e, msgs, resp); err != nil {
+				release()
+				return nil, err
+			}
+			resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+			return resp, nil
+		}
+
+		if err == nil {
+			err = errors.Errorf("endpoint responded %s", resp.Status)
+		}
+		e.failure(err)
+		lastErr = err
+
+		// endpoint cuối cùng thì trả về response lỗi để người gọi biết mã lỗi HTTP
+		if resp != nil {
+			if i == len(ranked)-1 {
+				resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+				return resp, nil
+			}
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		release()
+		if req.Context().Err() != nil {
+			return nil, req.Context().Err()
+		}
+	}
+	return nil, errors.Wrap(lastErr, "all rpc endpoints failed")
+}
+
+// filterEndpoint trả về endpoint đã tạo các filter mà `msgs` dùng, nil nếu `msgs` không dùng filter đã biết
+func (p *endpointPool) filterEndpoint(msgs []*jsonrpcMessage) (*endpoint, error) {
+	p.filtersMu.Lock()
+	defer p.filtersMu.Unlock()
+
+	var pinned *endpoint
+	for _, msg := range msgs {
+		if !filterMethods[msg.Method] {
+			continue
+		}
+		e, ok := p.filters[filterIDParam(msg.Params)]
+		if !ok {
+			continue
+		}
+		if pinned != nil && pinned != e {
+			return nil, errors.New("json-rpc batch uses filters of different endpoints")
+		}
+		pinned = e
+	}
+	return pinned, nil
+}
+
+// createsFilter trả về true nếu `msgs` có lời gọi tạo filter
+func createsFilter(msgs []*jsonrpcMessage) bool {
+	for _, msg := range msgs {
+		if newFilterMethods[msg.Method] {
+			return true
+		}
+	}
+	return false
+}
+
+// trackFilters ghi lại endpoint `e` của các filter vừa được tạo trong `msgs`
+// và xoá các filter vừa bị xoá hoặc node báo không còn tồn tại
+func (p *endpointPool) trackFilters(e *endpoint, msgs []*jsonrpcMessage, resp *http.Response) error {
+	calls := make(map[string]*jsonrpcMessage)
+	for _, msg := range msgs {
+		if newFilterMethods[msg.Method] || filterMethods[msg.Method] {
+			calls[string(msg.ID)] = msg
+		}
+	}
+	if len(calls) == 0 {
+		return nil
+	}
+
+	// đọc response để lấy filter id rồi trả lại body cho người gọi
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return errors.Wrap(err, "read response body error")
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	p.filtersMu.Lock()
+	defer p.filtersMu.Unlock()
+	for _, result := range decodeMessages(body) {
+		call, ok := calls[string(result.ID)]
+		if !ok {
+			continue
+		}
+
+		if newFilterMethods[call.Method] {
+			var filterID string
+			if result.Error == nil && json.Unmarshal(result.Result, &filterID) == nil {
+				p.filters[filterID] = e
+			}
+			continue
+		}
+		if call.Method == "eth_uninstallFilter" || result.Error != nil && IsFilterNotFoundError(result.Error) {
+			delete(p.filters, filterIDParam(call.Params))
+		}
+	}
+	return nil
+}
+
+// decodeMessages decode request hoặc response JSON-RPC đơn hay batch, trả về nil nếu không decode được
+func decodeMessages(body []byte) []*jsonrpcMessage {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var msgs []*jsonrpcMessage
+		if json.Unmarshal(body, &msgs) != nil {
+			return nil
+		}
+		return msgs
+	}
+	msg := new(jsonrpcMessage)
+	if json.Unmarshal(body, msg) != nil {
+		return nil
+	}
+	return []*jsonrpcMessage{msg}
+}
+
+// filterIDParam trả về filter id là params đầu tiên của lời gọi
+func filterIDParam(params json.RawMessage) string {
+	var args []string
+	if json.Unmarshal(params, &args) != nil || len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+// isFailoverStatus trả về true nếu mã lỗi HTTP cho thấy nên thử endpoint khác
+func isFailoverStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
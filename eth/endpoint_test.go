@@ -0,0 +1,163 @@
+package eth_test
+
+import (
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newMultiClient tạo client tới hai Node, `fast` được xếp trước `slow` nhờ latency của lần kiểm tra đầu tiên
+// mỗi Node trả về count khác nhau để biết lời gọi đã tới Node nào
+func newMultiClient(t *testing.T) (client *eth.Client, fast, slow *ethtest.Node) {
+	t.Helper()
+
+	fast, slow = ethtest.NewNode(), ethtest.NewNode()
+	t.Cleanup(fast.Close)
+	t.Cleanup(slow.Close)
+	fast.OnCall(counterAddress, counterABI, "count", []interface{}{alice}, big.NewInt(1))
+	slow.OnCall(counterAddress, counterABI, "count", []interface{}{alice}, big.NewInt(2))
+	slow.Inject("eth_blockNumber", ethtest.Fault{Delay: 50 * time.Millisecond, Times: 1})
+
+	client, err := eth.NewMultiClient([]string{fast.URL, slow.URL}, eth.WithHealthCheckInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client, fast, slow
+}
+
+// count gọi `count(alice)` và trả về Node đã trả lời theo kết quả
+func count(t *testing.T, client *eth.Client) int64 {
+	t.Helper()
+
+	var result struct {
+		Count *big.Int
+	}
+	if err := client.CallContractViewFunction(ethtest.Context(t), counterABI, counterAddress, &result, "count", alice); err != nil {
+		t.Fatalf("CallContractViewFunction: %v", err)
+	}
+	return result.Count.Int64()
+}
+
+func TestMultiClientFailover(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault ethtest.Fault
+	}{
+		{name: "server error", fault: ethtest.Fault{HTTPStatus: http.StatusBadGateway, Times: 1}},
+		{name: "rate limited", fault: ethtest.Fault{HTTPStatus: http.StatusTooManyRequests, Times: 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, fast, _ := newMultiClient(t)
+			if got := count(t, client); got != 1 {
+				t.Fatalf("first call answered by node %d, want the faster node 1", got)
+			}
+
+			fast.Inject("eth_call", test.fault)
+			if got := count(t, client); got != 2 {
+				t.Fatalf("call after fault answered by node %d, want failover to node 2", got)
+			}
+
+			// endpoint lỗi bị xếp sau cho tới khi tỉ lệ lỗi giảm
+			if got := count(t, client); got != 2 {
+				t.Fatalf("call after failover answered by node %d, want node 2", got)
+			}
+			statuses := client.Endpoints()
+			if statuses[0].Failures != 1 || statuses[0].ErrorRate <= statuses[1].ErrorRate {
+				t.Fatalf("endpoint statuses = %+v, want one failure on node 1", statuses)
+			}
+		})
+	}
+}
+
+func TestMultiClientSkipsLaggingEndpoint(t *testing.T) {
+	fast, slow := ethtest.NewNode(), ethtest.NewNode()
+	defer fast.Close()
+	defer slow.Close()
+	fast.OnCall(counterAddress, counterABI, "count", []interface{}{alice}, big.NewInt(1))
+	slow.OnCall(counterAddress, counterABI, "count", []interface{}{alice}, big.NewInt(2))
+	slow.Inject("eth_blockNumber", ethtest.Fault{Delay: 50 * time.Millisecond, Times: 1})
+	fast.SetHead(100)
+	slow.SetHead(110)
+
+	client, err := eth.NewMultiClient([]string{fast.URL, slow.URL}, eth.WithHealthCheckInterval(time.Hour), eth.WithMaxBlockLag(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if got := count(t, client); got != 2 {
+		t.Fatalf("call answered by node %d, want node 2 that is not lagging", got)
+	}
+	if statuses := client.Endpoints(); !statuses[0].Lagging || statuses[1].Lagging {
+		t.Fatalf("endpoint statuses = %+v, want node 1 lagging", statuses)
+	}
+}
+
+func TestMultiClientPinsFilters(t *testing.T) {
+	client, fast, slow := newMultiClient(t)
+	ctx := ethtest.Context(t)
+
+	filterID, err := client.EthNewFilterQuery(ctx, eth.FilterQuery{Addresses: []common.Address{counterAddress}})
+	if err != nil {
+		t.Fatalf("EthNewFilterQuery: %v", err)
+	}
+	if fast.RequestCount("eth_newFilter") != 1 {
+		t.Fatal("filter was not created on the faster node")
+	}
+
+	// endpoint tạo filter bị xếp sau nhưng các lời gọi filter vẫn phải tới endpoint đó
+	fast.Inject("eth_call", ethtest.Fault{HTTPStatus: http.StatusServiceUnavailable, Times: 1})
+	if got := count(t, client); got != 2 {
+		t.Fatalf("call answered by node %d, want failover to node 2", got)
+	}
+	if _, err = client.EthGetFilterChanges(ctx, filterID); err != nil {
+		t.Fatalf("EthGetFilterChanges: %v", err)
+	}
+	if err = client.EthUninstallFilter(ctx, filterID); err != nil {
+		t.Fatalf("EthUninstallFilter: %v", err)
+	}
+	if fast.RequestCount("eth_getFilterChanges") != 1 || fast.RequestCount("eth_uninstallFilter") != 1 {
+		t.Fatal("filter calls were not sent to the node that created the filter")
+	}
+	if slow.RequestCount("eth_getFilterChanges") != 0 || slow.RequestCount("eth_uninstallFilter") != 0 {
+		t.Fatal("filter calls were sent to a node without the filter")
+	}
+}
+
+func TestMultiClientDoesNotFailOverFilterCreation(t *testing.T) {
+	tests := []struct {
+		name string
+		// fail làm endpoint đầu tiên lỗi khi tạo filter
+		fail func(node *ethtest.Node)
+	}{
+		{name: "server error", fail: func(node *ethtest.Node) {
+			node.Inject("eth_newFilter", ethtest.Fault{HTTPStatus: http.StatusInternalServerError, Times: 1})
+		}},
+		{name: "transport error", fail: func(node *ethtest.Node) {
+			node.CloseClientConnections()
+			node.Close()
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, fast, slow := newMultiClient(t)
+			test.fail(fast)
+
+			_, err := client.EthNewFilterQuery(ethtest.Context(t), eth.FilterQuery{Addresses: []common.Address{counterAddress}})
+			if err == nil {
+				t.Fatal("EthNewFilterQuery succeeded on a failing endpoint")
+			}
+			if slow.RequestCount("eth_newFilter") != 0 {
+				t.Fatal("filter creation failed over to another endpoint")
+			}
+		})
+	}
+}
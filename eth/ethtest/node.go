@@ -0,0 +1,740 @@
+// Package ethtest cung cấp node JSON-RPC giả chạy trong process để test eth.Client và các contract wrapper
+// không cần kết nối tới node thật
+package ethtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// DefaultChainID là chain id mặc định của Node, giống chain id của bsc testnet
+var DefaultChainID = big.NewInt(97)
+
+// các mã lỗi JSON-RPC Node trả về
+const (
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeServerError    = -32000
+)
+
+// Node là node JSON-RPC giả chạy trên httptest.Server, trả lời theo trạng thái được dựng sẵn
+// hỗ trợ `eth_chainId`, `eth_blockNumber`, `eth_call`, `eth_getLogs`, `eth_newFilter`, `eth_getFilterChanges`
+// và `eth_uninstallFilter`, các method khác trả lời theo SetResult. Các hàm của Node an toàn khi gọi từ nhiều goroutine
+type Node struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	chainID *big.Int
+	head    uint64
+	calls   map[string]*callResult
+	results map[string]interface{}
+
+	// chain là các log của chain hiện tại, logs là các log theo thứ tự filter nhận, gồm cả log bị loại bỏ khi reorg
+	chain []types.Log
+	logs  []types.Log
+
+	filters      map[string]*filter
+	filterID     uint64
+	faults       map[string][]*Fault
+	requests     []Request
+	httpRequests int
+
+	maxLogResults int
+	maxLogRange   uint64
+}
+
+// Request là một lời gọi JSON-RPC Node đã nhận
+type Request struct {
+	Method string
+	Params json.RawMessage
+
+	// HTTPRequest là số thứ tự của request HTTP chứa lời gọi, các lời gọi cùng một JSON-RPC batch có cùng số
+	HTTPRequest int
+}
+
+// Fault là lỗi được giả lập cho một method
+type Fault struct {
+	// Error là lỗi JSON-RPC trả về thay cho kết quả
+	Error *eth.RPCError
+
+	// HTTPStatus khác 0 thì cả request HTTP trả về mã lỗi này
+	HTTPStatus int
+
+	// Delay là thời gian đợi trước khi trả lời, dùng để giả lập timeout
+	Delay time.Duration
+
+	// Times là số lần lỗi xảy ra, 0 là mãi mãi
+	Times int
+}
+
+type callResult struct {
+	result []byte
+	err    *eth.RPCError
+}
+
+type filter struct {
+	query logQuery
+	next  int
+}
+
+// NewNode khởi động Node với chain id DefaultChainID và chưa có block nào
+// người gọi phải đóng Node bằng Close
+func NewNode() *Node {
+	n := &Node{
+		chainID: DefaultChainID,
+		calls:   make(map[string]*callResult),
+		results: make(map[string]interface{}),
+		filters: make(map[string]*filter),
+		faults:  make(map[string][]*Fault),
+	}
+	n.Server = httptest.NewServer(http.HandlerFunc(n.serveHTTP))
+	return n
+}
+
+// SetChainID đặt chain id trả về bởi `eth_chainId`
+func (n *Node) SetChainID(chainID *big.Int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.chainID = chainID
+}
+
+// SetHead đặt block mới nhất của Node
+func (n *Node) SetHead(head uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.head = head
+}
+
+// Head trả về block mới nhất của Node
+func (n *Node) Head() uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.head
+}
+
+// OnCall đặt kết quả `eth_call` hàm `function` của contract `to` với tham số `args`, kết quả được pack từ `results`
+// panic nếu không pack được tham số hoặc kết quả
+func (n *Node) OnCall(to common.Address, contractABI abi.ABI, function string, args []interface{}, results ...interface{}) {
+	data, err := contractABI.Pack(function, args...)
+	if err != nil {
+		panic(fmt.Sprintf("ethtest pack %s args error: %v", function, err))
+	}
+	result, err := contractABI.Methods[function].Outputs.Pack(results...)
+	if err != nil {
+		panic(fmt.Sprintf("ethtest pack %s results error: %v", function, err))
+	}
+	n.OnCallData(to, data, result)
+}
+
+// OnCallRevert đặt `eth_call` hàm `function` của contract `to` với tham số `args` bị revert với lý do `reason`
+func (n *Node) OnCallRevert(to common.Address, contractABI abi.ABI, function string, args []interface{}, reason string) {
+	data, err := contractABI.Pack(function, args...)
+	if err != nil {
+		panic(fmt.Sprintf("ethtest pack %s args error: %v", function, err))
+	}
+
+	// Error(string) selector 0x08c379a0 theo sau là reason đã abi encode
+	stringType, _ := abi.NewType("string", "", nil)
+	encoded, _ := abi.Arguments{{Type: stringType}}.Pack(reason)
+	revertData := append([]byte{0x08, 0xc3, 0x79, 0xa0}, encoded...)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.calls[callKey(to, data)] = &callResult{err: &eth.RPCError{
+		Code:    3,
+		Message: "execution reverted: " + reason,
+		Data:    hexutil.Encode(revertData),
+	}}
+}
+
+// OnCallData đặt kết quả `eth_call` tới `to` với `data` là `result`
+func (n *Node) OnCallData(to common.Address, data []byte, result []byte) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.calls[callKey(to, data)] = &callResult{result: result}
+}
+
+// SetResult trả lời mọi lời gọi `method` bằng `result`, không phụ thuộc tham số
+// `result` được encode JSON như kết quả của node thật, ví dụ *hexutil.Big cho `eth_gasPrice`
+func (n *Node) SetResult(method string, result interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.results[method] = result
+}
+
+// AddLogs thêm các log vào chain, block mới nhất được nâng lên block của log nếu cần
+func (n *Node) AddLogs(logs ...types.Log) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.addLogs(logs)
+}
+
+// AddEvent thêm log của event `event` trong `contractABI` do contract `address` emit ở block `block` và trả về log đó
+// hash block là BlockHash(block), vị trí trong block là số log đã có ở block đó. Xem EventLog
+func (n *Node) AddEvent(block uint64, address common.Address, contractABI abi.ABI, event string, args ...interface{}) types.Log {
+	log := EventLog(address, contractABI, event, args...)
+	log.BlockNumber = block
+	log.BlockHash = BlockHash(block)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, l := range n.chain {
+		if l.BlockNumber == block {
+			log.Index++
+		}
+	}
+	log.TxHash = crypto.Keccak256Hash(log.BlockHash.Bytes(), big.NewInt(int64(log.Index)).Bytes())
+	n.addLogs([]types.Log{log})
+	return log
+}
+
+// EventLog tạo log của event `event` trong `contractABI` do contract `address` emit, chưa có thông tin block
+// `args` là các tham số của event theo thứ tự trong abi, tham số indexed được đưa vào topics
+// panic nếu abi không có event hoặc không pack được tham số
+func EventLog(address common.Address, contractABI abi.ABI, event string, args ...interface{}) types.Log {
+	e, ok := contractABI.Events[event]
+	if !ok {
+		panic(fmt.Sprintf("ethtest abi has no event %s", event))
+	}
+	if len(args) != len(e.Inputs) {
+		panic(fmt.Sprintf("ethtest event %s expects %d args, got %d", event, len(e.Inputs), len(args)))
+	}
+
+	log := types.Log{Address: address, Topics: []common.Hash{e.ID}}
+	var data []interface{}
+	for i, input := range e.Inputs {
+		if !input.Indexed {
+			data = append(data, args[i])
+			continue
+		}
+		topics, err := abi.MakeTopics([]interface{}{args[i]})
+		if err != nil {
+			panic(fmt.Sprintf("ethtest make %s topic %s error: %v", event, input.Name, err))
+		}
+		log.Topics = append(log.Topics, topics[0][0])
+	}
+
+	var err error
+	log.Data, err = e.Inputs.NonIndexed().Pack(data...)
+	if err != nil {
+		panic(fmt.Sprintf("ethtest pack %s data error: %v", event, err))
+	}
+	return log
+}
+
+// BlockHash trả về hash giả của block `number`, dùng cho log thêm bằng AddEvent
+func BlockHash(number uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("ethtest block"), new(big.Int).SetUint64(number).Bytes())
+}
+
+// Reorg giả lập reorg thay các block từ block `fromBlock` bằng các block chứa `logs`
+// các filter nhận lại log cũ từ block `fromBlock` với Removed là true rồi tới `logs`
+// block mới nhất là block lớn nhất của `logs`, hoặc block trước `fromBlock` nếu `logs` rỗng
+func (n *Node) Reorg(fromBlock uint64, logs ...types.Log) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var chain []types.Log
+	for _, log := range n.chain {
+		if log.BlockNumber < fromBlock {
+			chain = append(chain, log)
+			continue
+		}
+		log.Removed = true
+		n.logs = append(n.logs, log)
+	}
+	n.chain = chain
+
+	if fromBlock > 0 && n.head >= fromBlock {
+		n.head = fromBlock - 1
+	}
+	n.addLogs(logs)
+}
+
+func (n *Node) addLogs(logs []types.Log) {
+	for _, log := range logs {
+		n.logs = append(n.logs, log)
+		n.chain = append(n.chain, log)
+		if log.BlockNumber > n.head {
+			n.head = log.BlockNumber
+		}
+	}
+}
+
+// SetLogLimits giới hạn `eth_getLogs` như các node thật: trả về lỗi khi có nhiều hơn `maxResults` log
+// hoặc khoảng block lớn hơn `maxRange` block, 0 là không giới hạn
+func (n *Node) SetLogLimits(maxResults int, maxRange uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.maxLogResults = maxResults
+	n.maxLogRange = maxRange
+}
+
+// ExpireFilter xoá filter `filterID` như khi node xoá filter lâu không được đọc
+// các lần `eth_getFilterChanges` sau đó trả về lỗi "filter not found"
+func (n *Node) ExpireFilter(filterID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.filters, filterID)
+}
+
+// ExpireFilters xoá tất cả các filter đang có
+func (n *Node) ExpireFilters() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.filters = make(map[string]*filter)
+}
+
+// Filters trả về id của các filter đang có
+func (n *Node) Filters() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ids := make([]string, 0, len(n.filters))
+	for id := range n.filters {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Inject giả lập lỗi `fault` cho các lời gọi `method`, các lỗi của cùng method được dùng theo thứ tự thêm vào
+func (n *Node) Inject(method string, fault Fault) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.faults[method] = append(n.faults[method], &fault)
+}
+
+// Requests trả về các lời gọi Node đã nhận theo thứ tự
+func (n *Node) Requests() []Request {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]Request(nil), n.requests...)
+}
+
+// RequestCount trả về số lời gọi `method` Node đã nhận
+func (n *Node) RequestCount(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	count := 0
+	for _, request := range n.requests {
+		if request.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+type jsonrpcMessage struct {
+	Version string          `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *eth.RPCError   `json:"error,omitempty"`
+}
+
+func (n *Node) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	batch := len(bytes.TrimSpace(body)) > 0 && bytes.TrimSpace(body)[0] == '['
+	var msgs []*jsonrpcMessage
+	if batch {
+		err = json.Unmarshal(body, &msgs)
+	} else {
+		msg := new(jsonrpcMessage)
+		err = json.Unmarshal(body, msg)
+		msgs = []*jsonrpcMessage{msg}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n.mu.Lock()
+	n.httpRequests++
+	httpRequest := n.httpRequests
+	n.mu.Unlock()
+
+	responses := make([]*jsonrpcMessage, len(msgs))
+	for i, msg := range msgs {
+		fault := n.record(msg, httpRequest)
+		if fault != nil && fault.Delay > 0 {
+			select {
+			case <-time.After(fault.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault != nil && fault.HTTPStatus != 0 {
+			http.Error(w, http.StatusText(fault.HTTPStatus), fault.HTTPStatus)
+			return
+		}
+
+		responses[i] = &jsonrpcMessage{Version: "2.0", ID: msg.ID}
+		if fault != nil && fault.Error != nil {
+			responses[i].Error = fault.Error
+			continue
+		}
+		responses[i].Result, responses[i].Error = n.handle(msg.Method, msg.Params)
+		if responses[i].Error == nil && responses[i].Result == nil {
+			responses[i].Result = json.RawMessage("null")
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if batch {
+		json.NewEncoder(w).Encode(responses)
+	} else {
+		json.NewEncoder(w).Encode(responses[0])
+	}
+}
+
+// record ghi nhận lời gọi `msg` và trả về lỗi giả lập cho lời gọi nếu có
+func (n *Node) record(msg *jsonrpcMessage, httpRequest int) *Fault {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.requests = append(n.requests, Request{Method: msg.Method, Params: msg.Params, HTTPRequest: httpRequest})
+	faults := n.faults[msg.Method]
+	if len(faults) == 0 {
+		return nil
+	}
+	fault := faults[0]
+	if fault.Times > 0 {
+		fault.Times--
+		if fault.Times == 0 {
+			n.faults[msg.Method] = faults[1:]
+		}
+	}
+	return fault
+}
+
+func (n *Node) handle(method string, params json.RawMessage) (interface{}, *eth.RPCError) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var args []json.RawMessage
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, invalidParams(err)
+		}
+	}
+
+	if result, ok := n.results[method]; ok {
+		return result, nil
+	}
+
+	switch method {
+	case "eth_chainId":
+		return (*hexutil.Big)(n.chainID), nil
+	case "eth_blockNumber":
+		return hexutil.Uint64(n.head), nil
+	case "eth_call":
+		return n.call(args)
+	case "eth_getLogs":
+		if len(args) != 1 {
+			return nil, invalidParams(fmt.Errorf("expected 1 param, got %d", len(args)))
+		}
+		query, err := parseQuery(args[0])
+		if err != nil {
+			return nil, invalidParams(err)
+		}
+		return n.getLogs(query)
+	case "eth_newFilter":
+		if len(args) != 1 {
+			return nil, invalidParams(fmt.Errorf("expected 1 param, got %d", len(args)))
+		}
+		query, err := parseQuery(args[0])
+		if err != nil {
+			return nil, invalidParams(err)
+		}
+		n.filterID++
+		id := hexutil.EncodeUint64(n.filterID)
+		n.filters[id] = &filter{query: query, next: len(n.logs)}
+		return id, nil
+	case "eth_getFilterChanges":
+		f, err := n.filter(args)
+		if err != nil {
+			return nil, err
+		}
+		logs := n.logs[f.next:]
+		f.next = len(n.logs)
+		return n.match(f.query, logs, true), nil
+	case "eth_uninstallFilter":
+		var id string
+		if len(args) != 1 || json.Unmarshal(args[0], &id) != nil {
+			return nil, invalidParams(fmt.Errorf("invalid filter id"))
+		}
+		_, ok := n.filters[id]
+		delete(n.filters, id)
+		return ok, nil
+	default:
+		return nil, &eth.RPCError{
+			Code:    CodeMethodNotFound,
+			Message: fmt.Sprintf("the method %s does not exist/is not available", method),
+		}
+	}
+}
+
+func (n *Node) call(args []json.RawMessage) (interface{}, *eth.RPCError) {
+	if len(args) < 1 {
+		return nil, invalidParams(fmt.Errorf("missing call arguments"))
+	}
+
+	var msg struct {
+		To    *common.Address `json:"to"`
+		Data  hexutil.Bytes   `json:"data"`
+		Input hexutil.Bytes   `json:"input"`
+	}
+	if err := json.Unmarshal(args[0], &msg); err != nil {
+		return nil, invalidParams(err)
+	}
+	if msg.To == nil {
+		return nil, invalidParams(fmt.Errorf("missing call target"))
+	}
+	data := msg.Data
+	if len(data) == 0 {
+		data = msg.Input
+	}
+
+	result, ok := n.calls[callKey(*msg.To, data)]
+	if !ok {
+		return nil, &eth.RPCError{
+			Code:    CodeServerError,
+			Message: fmt.Sprintf("ethtest: no scripted eth_call to %s with data %s", msg.To.Hex(), hexutil.Encode(data)),
+		}
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+	return hexutil.Bytes(result.result), nil
+}
+
+func (n *Node) getLogs(query logQuery) (interface{}, *eth.RPCError) {
+	if n.maxLogRange > 0 && query.blockHash == nil {
+		fromBlock, toBlock := query.fromBlock, query.toBlock
+		if fromBlock == latestBlock {
+			fromBlock = int64(n.head)
+		}
+		if toBlock == latestBlock {
+			toBlock = int64(n.head)
+		}
+		if toBlock >= fromBlock && uint64(toBlock-fromBlock) >= n.maxLogRange {
+			return nil, &eth.RPCError{
+				Code:    CodeServerError,
+				Message: fmt.Sprintf("exceed maximum block range: %d", n.maxLogRange),
+			}
+		}
+	}
+
+	logs := n.match(query, n.chain, false)
+	if n.maxLogResults > 0 && len(logs) > n.maxLogResults {
+		return nil, &eth.RPCError{
+			Code:    -32005,
+			Message: fmt.Sprintf("query returned more than %d results", n.maxLogResults),
+		}
+	}
+	return logs, nil
+}
+
+func (n *Node) filter(args []json.RawMessage) (*filter, *eth.RPCError) {
+	var id string
+	if len(args) != 1 || json.Unmarshal(args[0], &id) != nil {
+		return nil, invalidParams(fmt.Errorf("invalid filter id"))
+	}
+	f, ok := n.filters[id]
+	if !ok {
+		return nil, &eth.RPCError{Code: CodeServerError, Message: "filter not found"}
+	}
+	return f, nil
+}
+
+// latestBlock là số block của tag "latest" và "pending", được thay bằng block mới nhất khi lọc log
+const latestBlock = -1
+
+type logQuery struct {
+	blockHash *common.Hash
+	fromBlock int64
+	toBlock   int64
+	addresses []common.Address
+	topics    [][]common.Hash
+}
+
+// parseQuery đọc filter query theo đúng quy tắc của geth: số block là chuỗi hex hoặc tag, topic là null, hash hoặc mảng hash
+func parseQuery(raw json.RawMessage) (logQuery, error) {
+	var arg struct {
+		BlockHash *common.Hash      `json:"blockHash"`
+		FromBlock *json.RawMessage  `json:"fromBlock"`
+		ToBlock   *json.RawMessage  `json:"toBlock"`
+		Address   json.RawMessage   `json:"address"`
+		Topics    []json.RawMessage `json:"topics"`
+	}
+	if err := json.Unmarshal(raw, &arg); err != nil {
+		return logQuery{}, err
+	}
+
+	query := logQuery{blockHash: arg.BlockHash, fromBlock: latestBlock, toBlock: latestBlock}
+	var err error
+	if arg.FromBlock != nil {
+		if query.fromBlock, err = parseBlock(*arg.FromBlock); err != nil {
+			return logQuery{}, err
+		}
+	}
+	if arg.ToBlock != nil {
+		if query.toBlock, err = parseBlock(*arg.ToBlock); err != nil {
+			return logQuery{}, err
+		}
+	}
+
+	if len(arg.Address) > 0 && string(arg.Address) != "null" {
+		if arg.Address[0] == '[' {
+			err = json.Unmarshal(arg.Address, &query.addresses)
+		} else {
+			var address common.Address
+			err = json.Unmarshal(arg.Address, &address)
+			query.addresses = []common.Address{address}
+		}
+		if err != nil {
+			return logQuery{}, err
+		}
+	}
+
+	for _, rawTopic := range arg.Topics {
+		var topics []common.Hash
+		switch {
+		case string(rawTopic) == "null":
+		case len(rawTopic) > 0 && rawTopic[0] == '[':
+			err = json.Unmarshal(rawTopic, &topics)
+		default:
+			var topic common.Hash
+			err = json.Unmarshal(rawTopic, &topic)
+			topics = []common.Hash{topic}
+		}
+		if err != nil {
+			return logQuery{}, err
+		}
+		query.topics = append(query.topics, topics)
+	}
+	return query, nil
+}
+
+func parseBlock(raw json.RawMessage) (int64, error) {
+	var tag string
+	if err := json.Unmarshal(raw, &tag); err != nil {
+		return 0, fmt.Errorf("invalid block number %s: %v", raw, err)
+	}
+	switch tag {
+	case "latest", "pending":
+		return latestBlock, nil
+	case "earliest":
+		return 0, nil
+	}
+	number, err := hexutil.DecodeUint64(tag)
+	return int64(number), err
+}
+
+// match trả về các log khớp `query`, nếu `filter` là true thì tag `latest` không giới hạn khoảng block
+func (n *Node) match(query logQuery, logs []types.Log, filter bool) []types.Log {
+	fromBlock, toBlock := uint64(query.fromBlock), uint64(query.toBlock)
+	if query.fromBlock == latestBlock {
+		fromBlock = n.head
+		if filter {
+			fromBlock = 0
+		}
+	}
+	if query.toBlock == latestBlock {
+		toBlock = n.head
+		if filter {
+			toBlock = math.MaxUint64
+		}
+	}
+	matched := make([]types.Log, 0)
+	for _, log := range logs {
+		if query.blockHash != nil {
+			if log.BlockHash != *query.blockHash {
+				continue
+			}
+		} else if log.BlockNumber < fromBlock || log.BlockNumber > toBlock {
+			continue
+		}
+		if !matchAddress(query.addresses, log.Address) || !matchTopics(query.topics, log.Topics) {
+			continue
+		}
+		matched = append(matched, log)
+	}
+	return matched
+}
+
+func matchAddress(addresses []common.Address, address common.Address) bool {
+	if len(addresses) == 0 {
+		return true
+	}
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func matchTopics(topics [][]common.Hash, logTopics []common.Hash) bool {
+	if len(topics) > len(logTopics) {
+		return false
+	}
+	for i, alternatives := range topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range alternatives {
+			if topic == logTopics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func callKey(to common.Address, data []byte) string {
+	return to.Hex() + hexutil.Encode(data)
+}
+
+func invalidParams(err error) *eth.RPCError {
+	return &eth.RPCError{Code: CodeInvalidParams, Message: "invalid argument: " + err.Error()}
+}
@@ -0,0 +1,241 @@
+package ethtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// ReplayURL là endpoint giả của client tạo bằng NewReplayClient, không có request nào thực sự được gửi tới
+const ReplayURL = "http://replay.invalid"
+
+// Interaction là một lời gọi JSON-RPC đã ghi lại cùng kết quả hoặc lỗi node trả về
+type Interaction struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *eth.RPCError   `json:"error,omitempty"`
+}
+
+// Recorder ghi lại các lời gọi JSON-RPC của client để lưu thành file fixture cho Replayer
+// lỗi đường truyền không được ghi lại
+type Recorder struct {
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder tạo Recorder rỗng
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Interceptor trả về eth.Interceptor ghi lại mọi lời gọi đi qua, dùng với eth.WithInterceptors
+// khi tạo client tới node thật, ví dụ bsc testnet
+func (r *Recorder) Interceptor() eth.Interceptor {
+	return func(ctx context.Context, call *eth.RPCCall, invoker eth.Invoker) error {
+		err := invoker(ctx, call)
+
+		interaction := Interaction{Method: call.Method, Params: compact(call.Params)}
+		if err == nil {
+			interaction.Result = compact(call.Result)
+		} else if rpcErr, ok := errors.Cause(err).(*eth.RPCError); ok {
+			interaction.Error = rpcErr
+		} else {
+			return err
+		}
+
+		r.mu.Lock()
+		r.interactions = append(r.interactions, interaction)
+		r.mu.Unlock()
+		return err
+	}
+}
+
+// Interactions trả về các lời gọi đã ghi lại theo thứ tự
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save ghi các lời gọi đã ghi lại vào file fixture `path`, tạo thư mục nếu chưa có
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.Interactions(), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "encode fixture error")
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "create fixture directory error")
+	}
+	if err = ioutil.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return errors.Wrap(err, "write fixture error")
+	}
+	return nil
+}
+
+// Replayer trả lời các lời gọi JSON-RPC bằng các lời gọi đã ghi lại, không cần kết nối mạng
+// lời gọi được khớp theo method và params, các lời gọi giống nhau được trả lời theo thứ tự đã ghi, mỗi kết quả chỉ dùng một lần.
+// Lời gọi không khớp hoặc gọi nhiều lần hơn lúc ghi làm request thất bại với lỗi nêu rõ lời gọi đó và được ghi vào Unmatched
+type Replayer struct {
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+	used         map[string]int
+	unmatched    []Interaction
+}
+
+// NewReplayer tạo Replayer từ các lời gọi `interactions`
+func NewReplayer(interactions []Interaction) *Replayer {
+	r := &Replayer{
+		interactions: make(map[string][]Interaction),
+		used:         make(map[string]int),
+	}
+	for _, interaction := range interactions {
+		key := interactionKey(interaction.Method, interaction.Params)
+		r.interactions[key] = append(r.interactions[key], interaction)
+	}
+	return r
+}
+
+// LoadReplayer tạo Replayer từ file fixture `path` do Recorder.Save ghi ra
+func LoadReplayer(path string) (*Replayer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read fixture error")
+	}
+
+	var interactions []Interaction
+	if err = json.Unmarshal(data, &interactions); err != nil {
+		return nil, errors.Wrapf(err, "decode fixture %s error", path)
+	}
+	return NewReplayer(interactions), nil
+}
+
+// NewReplayClient tạo client trả lời mọi lời gọi từ file fixture `path`, client không retry
+func NewReplayClient(path string, opts ...eth.Option) (*eth.Client, *Replayer, error) {
+	r, err := LoadReplayer(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts = append(opts, eth.WithInterceptors(r.Interceptor()), eth.WithRetryPolicy(eth.NoRetry))
+	client, err := eth.NewClient(ReplayURL, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, r, nil
+}
+
+// NewFixtureClient tạo client cho test hồi quy chạy từ file fixture `path`, không cần kết nối mạng.
+// Nếu `url` khác rỗng, client gọi tới node `url`, ví dụ bsc testnet hoặc một Node, và ghi lại fixture khi test kết thúc.
+// Khi replay, test thất bại nếu có lời gọi không có trong fixture hoặc lời gọi đã ghi chưa được dùng
+func NewFixtureClient(t testing.TB, path string, url string, opts ...eth.Option) *eth.Client {
+	t.Helper()
+
+	if url != "" {
+		recorder := NewRecorder()
+		client, err := eth.NewClient(url, append(opts, eth.WithInterceptors(recorder.Interceptor()))...)
+		if err != nil {
+			t.Fatalf("ethtest: create recording client error: %v", err)
+		}
+		t.Cleanup(func() {
+			client.Close()
+			if err := recorder.Save(path); err != nil {
+				t.Errorf("ethtest: save fixture %s error: %v", path, err)
+			}
+		})
+		return client
+	}
+
+	client, replayer, err := NewReplayClient(path, opts...)
+	if err != nil {
+		t.Fatalf("ethtest: create replay client error: %v", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		for _, interaction := range replayer.Unmatched() {
+			t.Errorf("ethtest: unmatched call %s %s", interaction.Method, interaction.Params)
+		}
+		for _, interaction := range replayer.Unused() {
+			t.Errorf("ethtest: unused recorded call %s %s", interaction.Method, interaction.Params)
+		}
+	})
+	return client
+}
+
+// Interceptor trả về eth.Interceptor trả lời lời gọi bằng kết quả đã ghi lại, không bao giờ gọi tới node
+// phải là interceptor cuối cùng trong chuỗi
+func (r *Replayer) Interceptor() eth.Interceptor {
+	return func(ctx context.Context, call *eth.RPCCall, invoker eth.Invoker) error {
+		interaction, ok := r.next(call.Method, call.Params)
+		if !ok {
+			return errors.Errorf("ethtest replay: no recorded response left for %s %s", call.Method, compact(call.Params))
+		}
+		if interaction.Error != nil {
+			return interaction.Error
+		}
+		call.Result = interaction.Result
+		return nil
+	}
+}
+
+// Unmatched trả về các lời gọi không có trong fixture hoặc gọi nhiều lần hơn lúc ghi
+func (r *Replayer) Unmatched() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Interaction(nil), r.unmatched...)
+}
+
+// Unused trả về các lời gọi đã ghi lại nhưng chưa được dùng, dùng để kiểm tra test gọi đủ các lời gọi như khi ghi
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for key, interactions := range r.interactions {
+		if r.used[key] < len(interactions) {
+			unused = append(unused, interactions[r.used[key]:]...)
+		}
+	}
+	return unused
+}
+
+func (r *Replayer) next(method string, params json.RawMessage) (Interaction, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := interactionKey(method, params)
+	interactions := r.interactions[key]
+	index := r.used[key]
+	if index >= len(interactions) {
+		// lặp lại kết quả cũ sẽ che mất việc client gọi thừa, ví dụ poll lại một filter đã hết log
+		r.unmatched = append(r.unmatched, Interaction{Method: method, Params: compact(params)})
+		return Interaction{}, false
+	}
+	r.used[key] = index + 1
+	return interactions[index], true
+}
+
+func interactionKey(method string, params json.RawMessage) string {
+	return method + string(compact(params))
+}
+
+// compact bỏ khoảng trắng trong `data` để so sánh params không phụ thuộc cách format
+func compact(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+	return buf.Bytes()
+}
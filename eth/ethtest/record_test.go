@@ -0,0 +1,65 @@
+package ethtest_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/common"
+	"testing"
+	"time"
+)
+
+func TestReplayerFailsWhenRecordedCallsAreExhausted(t *testing.T) {
+	query := eth.FilterQuery{
+		FromBlock: eth.AtBlock(0),
+		ToBlock:   eth.AtBlock(1),
+		Addresses: []common.Address{common.HexToAddress("0xc0")},
+	}
+
+	node := ethtest.NewNode()
+	defer node.Close()
+	recorder := ethtest.NewRecorder()
+	client, err := eth.NewClient(node.URL, eth.WithInterceptors(recorder.Interceptor()))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err = client.EthGetLogs(ctx, query); err != nil {
+		t.Fatalf("EthGetLogs: %v", err)
+	}
+
+	data, err := json.Marshal(recorder.Interactions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var interactions []ethtest.Interaction
+	if err = json.Unmarshal(data, &interactions); err != nil {
+		t.Fatal(err)
+	}
+	replayer := ethtest.NewReplayer(interactions)
+	replay, err := eth.NewClient(ethtest.ReplayURL, eth.WithInterceptors(replayer.Interceptor()), eth.WithRetryPolicy(eth.NoRetry))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer replay.Close()
+
+	if _, err = replay.EthGetLogs(ctx, query); err != nil {
+		t.Fatalf("first replayed EthGetLogs: %v", err)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Fatalf("unused calls after replay: %v", unused)
+	}
+
+	// lời gọi thứ hai không được ghi lại nên phải thất bại thay vì lặp lại kết quả trước
+	if _, err = replay.EthGetLogs(ctx, query); err == nil {
+		t.Fatal("second replayed EthGetLogs succeeded, want error")
+	}
+	unmatched := replayer.Unmatched()
+	if len(unmatched) != 1 || unmatched[0].Method != "eth_getLogs" {
+		t.Fatalf("unmatched = %v, want one eth_getLogs call", unmatched)
+	}
+}
@@ -0,0 +1,33 @@
+package ethtest
+
+import (
+	"context"
+	"github.com/adene-develop/adene-goeth/eth"
+	"testing"
+	"time"
+)
+
+// TestTimeout là thời gian tối đa của context do Context trả về
+const TestTimeout = 10 * time.Second
+
+// NewClient khởi động Node và tạo eth.Client tới Node đó với `opts`, cả hai được đóng khi test kết thúc
+func NewClient(t testing.TB, opts ...eth.Option) (*eth.Client, *Node) {
+	t.Helper()
+
+	node := NewNode()
+	t.Cleanup(node.Close)
+
+	client, err := eth.NewClient(node.URL, opts...)
+	if err != nil {
+		t.Fatalf("ethtest: create client error: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client, node
+}
+
+// Context trả về context hết hạn sau TestTimeout và bị huỷ khi test kết thúc
+func Context(t testing.TB) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), TestTimeout)
+	t.Cleanup(cancel)
+	return ctx
+}
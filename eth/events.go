@@ -0,0 +1,26 @@
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/pkg/errors"
+)
+
+// ErrRemovedEventNotHandled được các hàm Parse*Events trả về khi gặp log bị loại bỏ khỏi chain do reorg
+// nhưng người gọi không truyền receiver `rollback` để hoàn tác event
+var ErrRemovedEventNotHandled = errors.New("removed event needs a rollback receiver to be undone")
+
+// CheckRemovedEvents trả về ErrRemovedEventNotHandled nếu `changes` có log bị loại bỏ do reorg của một trong các event `events`
+// các hàm Parse*Events gọi trước khi chuyển event nào khi không có receiver `rollback`, để không chuyển dở một phần `changes`
+func CheckRemovedEvents(changes []*FilterChange, events ...abi.Event) error {
+	for _, change := range changes {
+		if !change.Removed {
+			continue
+		}
+		for _, event := range events {
+			if change.EventID() == event.ID {
+				return errors.Wrapf(ErrRemovedEventNotHandled, "%s log %s of block %s", event.Name, change.LogIndex, change.BlockHash.Hex())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package eth
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+)
+
+// FilterQuery là điều kiện lọc log dùng chung cho `eth_newFilter`, `eth_getLogs` và subscription `logs`
+type FilterQuery struct {
+	// BlockHash chỉ lọc log trong block có hash này, khi đặt thì FromBlock và ToBlock phải để trống
+	BlockHash *common.Hash
+
+	// FromBlock và ToBlock là khoảng block cần lọc, chỉ dùng số block hoặc tag, giá trị rỗng là `latest`
+	FromBlock BlockTag
+	ToBlock   BlockTag
+
+	// Addresses là các contract cần lọc, rỗng là mọi contract
+	Addresses []common.Address
+
+	// Topics là điều kiện theo vị trí topic: log khớp nếu topic tại mỗi vị trí là một trong các hash của vị trí đó
+	// vị trí nil hoặc rỗng khớp mọi topic
+	Topics [][]common.Hash
+}
+
+// NewFilterQueryFrom chuyển ethereum.FilterQuery của go-ethereum thành FilterQuery
+// giống go-ethereum, FromBlock nil là block 0 và ToBlock nil là block mới nhất
+func NewFilterQueryFrom(query ethereum.FilterQuery) FilterQuery {
+	q := FilterQuery{
+		BlockHash: query.BlockHash,
+		Addresses: query.Addresses,
+		Topics:    query.Topics,
+	}
+	if query.BlockHash == nil {
+		q.FromBlock = AtBlock(0)
+	}
+	if query.FromBlock != nil {
+		q.FromBlock = AtBlockNumber(query.FromBlock)
+	}
+	if query.ToBlock != nil {
+		q.ToBlock = AtBlockNumber(query.ToBlock)
+	}
+	return q
+}
+
+// Ethereum chuyển FilterQuery thành ethereum.FilterQuery để dùng với Backend, `head` là số của block mới nhất
+// go-ethereum coi FromBlock nil là block 0 nên FromBlock `latest` và `pending` thành `head`,
+// ToBlock `latest` và `pending` thành nil, `earliest` thành block 0
+func (q FilterQuery) Ethereum(head uint64) ethereum.FilterQuery {
+	query := ethereum.FilterQuery{
+		BlockHash: q.BlockHash,
+		FromBlock: filterBlockNumber(q.FromBlock),
+		ToBlock:   filterBlockNumber(q.ToBlock),
+		Addresses: q.Addresses,
+		Topics:    q.Topics,
+	}
+	if q.BlockHash == nil && query.FromBlock == nil {
+		query.FromBlock = new(big.Int).SetUint64(head)
+	}
+	return query
+}
+
+// MarshalJSON mã hoá FilterQuery đúng theo đặc tả JSON-RPC: số block dạng hex, vị trí topic trống là null
+func (q FilterQuery) MarshalJSON() ([]byte, error) {
+	arg := map[string]interface{}{}
+
+	if q.BlockHash != nil {
+		if !q.FromBlock.isZero() || !q.ToBlock.isZero() {
+			return nil, errors.New("filter query cannot have both block hash and block range")
+		}
+		arg["blockHash"] = *q.BlockHash
+	} else {
+		for key, block := range map[string]BlockTag{"fromBlock": q.FromBlock, "toBlock": q.ToBlock} {
+			if block.isZero() {
+				continue
+			}
+			if block.hash != nil {
+				return nil, errors.Errorf("filter query %s must be a block number or tag, got block hash", key)
+			}
+			arg[key] = block
+		}
+	}
+
+	switch len(q.Addresses) {
+	case 0:
+	case 1:
+		arg["address"] = q.Addresses[0]
+	default:
+		arg["address"] = q.Addresses
+	}
+
+	if len(q.Topics) > 0 {
+		topics := make([]interface{}, len(q.Topics))
+		for i, position := range q.Topics {
+			switch len(position) {
+			case 0:
+				topics[i] = nil
+			case 1:
+				topics[i] = position[0]
+			default:
+				topics[i] = position
+			}
+		}
+		arg["topics"] = topics
+	}
+
+	return json.Marshal(arg)
+}
+
+// isZero trả về true nếu BlockTag chưa được đặt
+func (b BlockTag) isZero() bool {
+	return b.tag == "" && b.number == nil && b.hash == nil
+}
+
+// parseFilterBlock đọc số block dạng thập phân, hex có tiền tố `0x` hoặc tag, chuỗi rỗng là chưa đặt
+func parseFilterBlock(block string) (BlockTag, error) {
+	switch block {
+	case "":
+		return BlockTag{}, nil
+	case "latest", "pending", "earliest":
+		return BlockTag{tag: block}, nil
+	}
+
+	if strings.HasPrefix(block, "0x") {
+		number, err := hexutil.DecodeBig(block)
+		if err != nil {
+			return BlockTag{}, errors.Wrapf(err, "invalid block number %s", block)
+		}
+		return AtBlockNumber(number), nil
+	}
+
+	number, ok := new(big.Int).SetString(block, 10)
+	if !ok || number.Sign() < 0 {
+		return BlockTag{}, errors.Errorf("invalid block number %s", block)
+	}
+	return AtBlockNumber(number), nil
+}
+
+func filterBlockNumber(block BlockTag) *big.Int {
+	switch {
+	case block.number != nil:
+		return new(big.Int).Set(block.number)
+	case block.tag == "earliest":
+		return new(big.Int)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,61 @@
+package eth_test
+
+import (
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum"
+	"math/big"
+	"testing"
+)
+
+func TestFilterQueryEthereum(t *testing.T) {
+	const head = 100
+
+	tests := []struct {
+		name     string
+		query    eth.FilterQuery
+		from, to *big.Int
+	}{
+		{"empty range is head", eth.FilterQuery{}, big.NewInt(head), nil},
+		{"latest", eth.FilterQuery{FromBlock: eth.LatestBlock(), ToBlock: eth.LatestBlock()}, big.NewInt(head), nil},
+		{"pending", eth.FilterQuery{FromBlock: eth.PendingBlock(), ToBlock: eth.PendingBlock()}, big.NewInt(head), nil},
+		{"earliest", eth.FilterQuery{FromBlock: eth.EarliestBlock(), ToBlock: eth.EarliestBlock()}, big.NewInt(0), big.NewInt(0)},
+		{"numbers", eth.FilterQuery{FromBlock: eth.AtBlock(5), ToBlock: eth.AtBlock(7)}, big.NewInt(5), big.NewInt(7)},
+		{"from go-ethereum nil range", eth.NewFilterQueryFrom(ethereum.FilterQuery{}), big.NewInt(0), nil},
+		{"from go-ethereum range", eth.NewFilterQueryFrom(ethereum.FilterQuery{FromBlock: big.NewInt(3), ToBlock: big.NewInt(4)}), big.NewInt(3), big.NewInt(4)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := tt.query.Ethereum(head)
+			if !equalBig(query.FromBlock, tt.from) || !equalBig(query.ToBlock, tt.to) {
+				t.Fatalf("range = %v..%v, want %v..%v", query.FromBlock, query.ToBlock, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestEthNewFilterBlockNumbers(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	ctx := ethtest.Context(t)
+
+	for _, block := range []string{"0x10", "16", "latest"} {
+		if _, err := client.EthNewFilter(ctx, block, "", nil, nil); err != nil {
+			t.Fatalf("EthNewFilter(%q): %v", block, err)
+		}
+	}
+	for _, block := range []string{"0X10", "0x", "-1", "head"} {
+		if _, err := client.EthNewFilter(ctx, block, "", nil, nil); err == nil {
+			t.Fatalf("EthNewFilter(%q) succeeded, want error", block)
+		}
+	}
+	if got := node.RequestCount("eth_newFilter"); got != 3 {
+		t.Fatalf("node got %d eth_newFilter requests, want 3", got)
+	}
+}
+
+func equalBig(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
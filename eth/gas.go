@@ -0,0 +1,216 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"math/big"
+	"sort"
+)
+
+// ErrGasPriceTooHigh được trả về khi giá gas vượt quá mức trần của client
+var ErrGasPriceTooHigh = errors.New("gas price exceeds client ceiling")
+
+// GasFees là giá gas dùng cho một transaction
+// GasPrice khác nil thì gửi transaction legacy, ngược lại gửi transaction EIP-1559 với GasTipCap và GasFeeCap
+type GasFees struct {
+	GasPrice  *big.Int
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+// IsLegacy trả về true nếu transaction dùng giá gas legacy
+func (f *GasFees) IsLegacy() bool {
+	return f.GasPrice != nil
+}
+
+// MaxPrice trả về giá cao nhất cho mỗi đơn vị gas mà transaction có thể phải trả
+func (f *GasFees) MaxPrice() *big.Int {
+	if f.IsLegacy() {
+		return f.GasPrice
+	}
+	return f.GasFeeCap
+}
+
+// GasStrategy quyết định giá gas cho các transaction gửi từ Client
+type GasStrategy interface {
+	GasFees(ctx context.Context, client *Client) (*GasFees, error)
+}
+
+// NewFixedGasPrice trả về GasStrategy luôn dùng giá gas legacy `gasPrice`, trả về lỗi nếu `gasPrice` nil hoặc âm
+func NewFixedGasPrice(gasPrice *big.Int) (GasStrategy, error) {
+	if gasPrice == nil || gasPrice.Sign() < 0 {
+		return nil, errors.Errorf("invalid fixed gas price %v", gasPrice)
+	}
+	return &fixedGasPrice{gasPrice: new(big.Int).Set(gasPrice)}, nil
+}
+
+type fixedGasPrice struct {
+	gasPrice *big.Int
+}
+
+func (s *fixedGasPrice) GasFees(ctx context.Context, client *Client) (*GasFees, error) {
+	return &GasFees{GasPrice: new(big.Int).Set(s.gasPrice)}, nil
+}
+
+// NewSuggestedGasPrice trả về GasStrategy dùng giá gas legacy theo `eth_gasPrice` nhân với `multiplier`
+// nếu `cap` khác nil thì giá gas không vượt quá `cap`. Trả về lỗi nếu `multiplier` không lớn hơn 0
+func NewSuggestedGasPrice(multiplier float64, cap *big.Int) (GasStrategy, error) {
+	if !(multiplier > 0) {
+		return nil, errors.Errorf("invalid gas price multiplier %v", multiplier)
+	}
+	return &suggestedGasPrice{
+		multiplier: multiplier,
+		cap:        cap,
+	}, nil
+}
+
+type suggestedGasPrice struct {
+	multiplier float64
+	cap        *big.Int
+}
+
+func (s *suggestedGasPrice) GasFees(ctx context.Context, client *Client) (*GasFees, error) {
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "suggest gas price error")
+	}
+
+	gasPrice = mulFloat(gasPrice, s.multiplier)
+	if s.cap != nil && gasPrice.Cmp(s.cap) > 0 {
+		gasPrice = new(big.Int).Set(s.cap)
+	}
+	return &GasFees{GasPrice: gasPrice}, nil
+}
+
+// NewFeeHistoryGasStrategy trả về GasStrategy cho các mạng EIP-1559 dựa trên `eth_feeHistory`
+// tip là trung vị của phần thưởng tại percentile `percentile` trong `blocks` block gần nhất
+// fee cap là base fee của block tiếp theo nhân với `baseFeeMultiplier` cộng với tip
+// trả về lỗi nếu `blocks` không lớn hơn 0, `percentile` ngoài khoảng [0, 100] hoặc `baseFeeMultiplier` không lớn hơn 0
+func NewFeeHistoryGasStrategy(blocks int, percentile float64, baseFeeMultiplier float64) (GasStrategy, error) {
+	if blocks <= 0 {
+		return nil, errors.Errorf("invalid fee history blocks %d", blocks)
+	}
+	if !(percentile >= 0 && percentile <= 100) {
+		return nil, errors.Errorf("invalid fee history percentile %v", percentile)
+	}
+	if !(baseFeeMultiplier > 0) {
+		return nil, errors.Errorf("invalid base fee multiplier %v", baseFeeMultiplier)
+	}
+	return &feeHistoryGasStrategy{
+		blocks:            blocks,
+		percentile:        percentile,
+		baseFeeMultiplier: baseFeeMultiplier,
+	}, nil
+}
+
+type feeHistoryGasStrategy struct {
+	blocks            int
+	percentile        float64
+	baseFeeMultiplier float64
+}
+
+func (s *feeHistoryGasStrategy) GasFees(ctx context.Context, client *Client) (*GasFees, error) {
+	history, err := client.FeeHistory(ctx, s.blocks, []float64{s.percentile})
+	if err != nil {
+		return nil, err
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, errors.New("fee history has no base fee, chain does not support EIP-1559")
+	}
+
+	tips := make([]*big.Int, 0, len(history.Reward))
+	for _, reward := range history.Reward {
+		if len(reward) > 0 {
+			tips = append(tips, reward[0])
+		}
+	}
+
+	tip := new(big.Int)
+	if len(tips) > 0 {
+		sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+		tip.Set(tips[len(tips)/2])
+	}
+
+	// phần tử cuối của base fee là base fee của block tiếp theo
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+	feeCap := new(big.Int).Add(mulFloat(baseFee, s.baseFeeMultiplier), tip)
+	return &GasFees{
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+	}, nil
+}
+
+// FeeHistory là kết quả của `eth_feeHistory`
+type FeeHistory struct {
+	OldestBlock  *big.Int
+	Reward       [][]*big.Int
+	BaseFee      []*big.Int
+	GasUsedRatio []float64
+}
+
+// FeeHistory gọi `eth_feeHistory` cho `blocks` block gần nhất với các percentile `percentiles`
+func (c *Client) FeeHistory(ctx context.Context, blocks int, percentiles []float64) (*FeeHistory, error) {
+	var result struct {
+		OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+		Reward       [][]*hexutil.Big `json:"reward"`
+		BaseFee      []*hexutil.Big   `json:"baseFeePerGas"`
+		GasUsedRatio []float64        `json:"gasUsedRatio"`
+	}
+
+	if c.rpc == nil {
+		return nil, ErrNoRPC
+	}
+
+	err := c.retry(ctx, func(ctx context.Context) error {
+		return c.RpcClient().CallContext(ctx, &result, "eth_feeHistory", hexutil.Uint(blocks), rpc.LatestBlockNumber, percentiles)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "call fee history error")
+	}
+
+	history := &FeeHistory{
+		OldestBlock:  (*big.Int)(result.OldestBlock),
+		Reward:       make([][]*big.Int, len(result.Reward)),
+		BaseFee:      make([]*big.Int, len(result.BaseFee)),
+		GasUsedRatio: result.GasUsedRatio,
+	}
+	for i, rewards := range result.Reward {
+		history.Reward[i] = make([]*big.Int, len(rewards))
+		for j, reward := range rewards {
+			history.Reward[i][j] = (*big.Int)(reward)
+		}
+	}
+	for i, baseFee := range result.BaseFee {
+		history.BaseFee[i] = (*big.Int)(baseFee)
+	}
+	return history, nil
+}
+
+// gasFees lấy giá gas theo GasStrategy của client và kiểm tra mức trần
+func (c *Client) gasFees(ctx context.Context) (*GasFees, error) {
+	fees, err := c.gasStrategy.GasFees(ctx, c)
+	if err != nil {
+		return nil, errors.Wrap(err, "client gas strategy error")
+	}
+	if err = c.checkGasCeiling(fees); err != nil {
+		return nil, err
+	}
+	return fees, nil
+}
+
+func (c *Client) checkGasCeiling(fees *GasFees) error {
+	if c.maxGasPrice != nil && fees.MaxPrice().Cmp(c.maxGasPrice) > 0 {
+		return errors.Wrapf(ErrGasPriceTooHigh, "gas price %s, ceiling %s", fees.MaxPrice(), c.maxGasPrice)
+	}
+	return nil
+}
+
+func mulFloat(value *big.Int, multiplier float64) *big.Int {
+	if multiplier == 1 {
+		return new(big.Int).Set(value)
+	}
+	result, _ := new(big.Float).Mul(new(big.Float).SetInt(value), big.NewFloat(multiplier)).Int(nil)
+	return result
+}
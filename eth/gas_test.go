@@ -0,0 +1,211 @@
+package eth_test
+
+import (
+	stderrors "errors"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestGasStrategyValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		new     func() (eth.GasStrategy, error)
+		wantErr bool
+	}{
+		{"fixed", func() (eth.GasStrategy, error) { return eth.NewFixedGasPrice(big.NewInt(5)) }, false},
+		{"fixed zero", func() (eth.GasStrategy, error) { return eth.NewFixedGasPrice(new(big.Int)) }, false},
+		{"fixed nil", func() (eth.GasStrategy, error) { return eth.NewFixedGasPrice(nil) }, true},
+		{"fixed negative", func() (eth.GasStrategy, error) { return eth.NewFixedGasPrice(big.NewInt(-1)) }, true},
+		{"suggested", func() (eth.GasStrategy, error) { return eth.NewSuggestedGasPrice(1.5, nil) }, false},
+		{"suggested zero multiplier", func() (eth.GasStrategy, error) { return eth.NewSuggestedGasPrice(0, nil) }, true},
+		{"suggested NaN multiplier", func() (eth.GasStrategy, error) { return eth.NewSuggestedGasPrice(math.NaN(), nil) }, true},
+		{"fee history", func() (eth.GasStrategy, error) { return eth.NewFeeHistoryGasStrategy(10, 50, 2) }, false},
+		{"fee history no blocks", func() (eth.GasStrategy, error) { return eth.NewFeeHistoryGasStrategy(0, 50, 2) }, true},
+		{"fee history negative percentile", func() (eth.GasStrategy, error) { return eth.NewFeeHistoryGasStrategy(10, -1, 2) }, true},
+		{"fee history percentile over 100", func() (eth.GasStrategy, error) { return eth.NewFeeHistoryGasStrategy(10, 101, 2) }, true},
+		{"fee history NaN percentile", func() (eth.GasStrategy, error) { return eth.NewFeeHistoryGasStrategy(10, math.NaN(), 2) }, true},
+		{"fee history zero multiplier", func() (eth.GasStrategy, error) { return eth.NewFeeHistoryGasStrategy(10, 50, 0) }, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			strategy, err := test.new()
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("strategy accepted invalid parameters")
+				}
+				return
+			}
+			if err != nil || strategy == nil {
+				t.Fatalf("strategy error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSuggestedGasPrice(t *testing.T) {
+	tests := []struct {
+		name       string
+		multiplier float64
+		cap        *big.Int
+		want       int64
+	}{
+		{name: "suggested", multiplier: 1, want: 1000},
+		{name: "multiplied", multiplier: 1.25, want: 1250},
+		{name: "below cap", multiplier: 2, cap: big.NewInt(3000), want: 2000},
+		{name: "capped", multiplier: 2, cap: big.NewInt(1500), want: 1500},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, node := ethtest.NewClient(t)
+			node.SetResult("eth_gasPrice", (*hexutil.Big)(big.NewInt(1000)))
+
+			strategy, err := eth.NewSuggestedGasPrice(test.multiplier, test.cap)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fees, err := strategy.GasFees(ethtest.Context(t), client)
+			if err != nil {
+				t.Fatalf("GasFees: %v", err)
+			}
+			if !fees.IsLegacy() || fees.GasPrice.Int64() != test.want {
+				t.Fatalf("GasFees = %+v, want legacy gas price %d", fees, test.want)
+			}
+		})
+	}
+}
+
+func TestFeeHistoryGasStrategy(t *testing.T) {
+	type feeHistory struct {
+		OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+		Reward       [][]*hexutil.Big `json:"reward"`
+		BaseFee      []*hexutil.Big   `json:"baseFeePerGas"`
+		GasUsedRatio []float64        `json:"gasUsedRatio"`
+	}
+	fee := func(value int64) *hexutil.Big {
+		return (*hexutil.Big)(big.NewInt(value))
+	}
+
+	tests := []struct {
+		name       string
+		history    feeHistory
+		multiplier float64
+		wantTip    int64
+		wantFeeCap int64
+		wantErr    bool
+	}{
+		{
+			name: "median tip",
+			history: feeHistory{
+				Reward:  [][]*hexutil.Big{{fee(30)}, {fee(10)}, {fee(20)}},
+				BaseFee: []*hexutil.Big{fee(90), fee(95), fee(100), fee(110)},
+			},
+			multiplier: 2,
+			wantTip:    20,
+			wantFeeCap: 2*110 + 20,
+		},
+		{
+			name: "empty blocks have no reward",
+			history: feeHistory{
+				Reward:  [][]*hexutil.Big{{}, {fee(7)}, {}},
+				BaseFee: []*hexutil.Big{fee(100), fee(100)},
+			},
+			multiplier: 1,
+			wantTip:    7,
+			wantFeeCap: 107,
+		},
+		{
+			name:       "no reward",
+			history:    feeHistory{BaseFee: []*hexutil.Big{fee(100)}},
+			multiplier: 1.5,
+			wantTip:    0,
+			wantFeeCap: 150,
+		},
+		{
+			name:       "no base fee",
+			history:    feeHistory{Reward: [][]*hexutil.Big{{fee(1)}}},
+			multiplier: 1,
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, node := ethtest.NewClient(t)
+			test.history.OldestBlock = fee(1)
+			node.SetResult("eth_feeHistory", test.history)
+
+			strategy, err := eth.NewFeeHistoryGasStrategy(3, 50, test.multiplier)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fees, err := strategy.GasFees(ethtest.Context(t), client)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("GasFees = %+v, want error", fees)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GasFees: %v", err)
+			}
+			if fees.IsLegacy() || fees.GasTipCap.Int64() != test.wantTip || fees.GasFeeCap.Int64() != test.wantFeeCap {
+				t.Fatalf("GasFees = tip %s, fee cap %s, want tip %d, fee cap %d", fees.GasTipCap, fees.GasFeeCap, test.wantTip, test.wantFeeCap)
+			}
+		})
+	}
+}
+
+func TestClientMaxGasPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		gasPrice int64
+		wantErr  bool
+	}{
+		{name: "below ceiling", gasPrice: params.GWei},
+		{name: "at ceiling", gasPrice: 2 * params.GWei},
+		{name: "above ceiling", gasPrice: 2*params.GWei + 1, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key, err := crypto.GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			from := crypto.PubkeyToAddress(key.PublicKey)
+			sim := backends.NewSimulatedBackend(core.GenesisAlloc{from: {Balance: big.NewInt(params.Ether)}}, 8_000_000)
+			defer sim.Close()
+
+			strategy, err := eth.NewFixedGasPrice(big.NewInt(test.gasPrice))
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := eth.AsClient(sim,
+				eth.WithPrivateKey(key),
+				eth.WithChainID(params.AllEthashProtocolChanges.ChainID),
+				eth.WithGasStrategy(strategy),
+				eth.WithMaxGasPrice(big.NewInt(2*params.GWei)),
+			)
+
+			to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+			_, err = client.SendTransaction(ethtest.Context(t), to, big.NewInt(1), nil)
+			if test.wantErr != stderrors.Is(err, eth.ErrGasPriceTooHigh) {
+				t.Fatalf("SendTransaction error = %v, want ErrGasPriceTooHigh %v", err, test.wantErr)
+			}
+			if !test.wantErr && err != nil {
+				t.Fatalf("SendTransaction: %v", err)
+			}
+		})
+	}
+}
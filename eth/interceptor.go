@@ -0,0 +1,335 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// RPCCall là một lời gọi JSON-RPC đi qua chuỗi Interceptor của client
+// gồm cả lời gọi từ ethclient lẫn lời gọi trực tiếp bằng rpc.Client
+type RPCCall struct {
+	Method string
+
+	// Params là mảng params dạng JSON
+	Params json.RawMessage
+
+	// Result là kết quả dạng JSON, được đặt sau khi Invoker trả về thành công
+	// interceptor có thể tự đặt Result và không gọi Invoker, ví dụ khi trả kết quả từ cache
+	Result json.RawMessage
+}
+
+// Invoker gửi `call` tới interceptor tiếp theo trong chuỗi, interceptor cuối cùng gửi tới node
+// lỗi JSON-RPC node trả về có kiểu *RPCError, các lỗi khác là lỗi đường truyền
+type Invoker func(ctx context.Context, call *RPCCall) error
+
+// Interceptor bọc mỗi lời gọi JSON-RPC của client, dùng cho log, metrics, tracing, cache hay giả lập lỗi
+// interceptor gọi `invoker` để tiếp tục chuỗi, hoặc trả về luôn để bỏ qua node
+type Interceptor func(ctx context.Context, call *RPCCall, invoker Invoker) error
+
+// RPCError là lỗi JSON-RPC trong response của node
+// interceptor trả về *RPCError để giả lập lỗi node trả về
+type RPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("json-rpc error %d", e.Code)
+	}
+	return e.Message
+}
+
+// ErrorCode trả về mã lỗi JSON-RPC, RPCError thoả mãn rpc.Error
+func (e *RPCError) ErrorCode() int {
+	return e.Code
+}
+
+// ErrorData trả về dữ liệu đi kèm lỗi, RPCError thoả mãn rpc.DataError
+func (e *RPCError) ErrorData() interface{} {
+	return e.Data
+}
+
+// WithInterceptors thêm các Interceptor bọc mọi lời gọi JSON-RPC của client
+// interceptor đứng trước bọc ngoài interceptor đứng sau, chỉ áp dụng cho endpoint http(s),
+// client tới endpoint websocket hoặc ipc bỏ qua các interceptor
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+type jsonrpcMessage struct {
+	Version string          `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// interceptorTransport là http.RoundTripper tách request JSON-RPC thành từng lời gọi, cho mỗi lời gọi
+// đi qua chuỗi Interceptor rồi gom các lời gọi tới được node vào một request gửi qua `next`
+type interceptorTransport struct {
+	interceptors []Interceptor
+	next         http.RoundTripper
+}
+
+func newInterceptorTransport(interceptors []Interceptor, next http.RoundTripper) http.RoundTripper {
+	if len(interceptors) == 0 {
+		return next
+	}
+	return &interceptorTransport{
+		interceptors: interceptors,
+		next:         next,
+	}
+}
+
+func (t *interceptorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "read request body error")
+	}
+
+	batch := len(bytes.TrimSpace(body)) > 0 && bytes.TrimSpace(body)[0] == '['
+	var msgs []*jsonrpcMessage
+	if batch {
+		err = json.Unmarshal(body, &msgs)
+	} else {
+		msg := new(jsonrpcMessage)
+		err = json.Unmarshal(body, msg)
+		msgs = []*jsonrpcMessage{msg}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "decode json-rpc request error")
+	}
+
+	collector := &callCollector{
+		active: len(msgs),
+		send: func(calls []*pendingCall) {
+			t.send(req, calls, batch)
+		},
+	}
+
+	calls := make([]*RPCCall, len(msgs))
+	errs := make([]error, len(msgs))
+	var wg sync.WaitGroup
+	for i, msg := range msgs {
+		calls[i] = &RPCCall{Method: msg.Method, Params: msg.Params}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer collector.done()
+			errs[i] = t.invoke(req.Context(), calls[i], 0, collector.invoke)
+		}(i)
+	}
+	wg.Wait()
+
+	responses := make([]*jsonrpcMessage, len(msgs))
+	for i, msg := range msgs {
+		responses[i] = &jsonrpcMessage{Version: "2.0", ID: msg.ID}
+		if errs[i] == nil {
+			responses[i].Result = calls[i].Result
+			if len(responses[i].Result) == 0 {
+				responses[i].Result = json.RawMessage("null")
+			}
+			continue
+		}
+
+		// lỗi đường truyền của một lời gọi làm hỏng cả request, giống như khi không có interceptor
+		rpcErr, ok := errors.Cause(errs[i]).(*RPCError)
+		if !ok {
+			return nil, errs[i]
+		}
+		responses[i].Error = rpcErr
+	}
+
+	var data []byte
+	if batch {
+		data, err = json.Marshal(responses)
+	} else {
+		data, err = json.Marshal(responses[0])
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "encode json-rpc response error")
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+// invoke gọi interceptor thứ `index`, sau interceptor cuối cùng là `last`
+func (t *interceptorTransport) invoke(ctx context.Context, call *RPCCall, index int, last Invoker) error {
+	if index == len(t.interceptors) {
+		return last(ctx, call)
+	}
+	return t.interceptors[index](ctx, call, func(ctx context.Context, call *RPCCall) error {
+		return t.invoke(ctx, call, index+1, last)
+	})
+}
+
+// send gửi các lời gọi tới được node trong một request qua transport tiếp theo
+func (t *interceptorTransport) send(req *http.Request, calls []*pendingCall, batch bool) {
+	err := t.roundTrip(req, calls, batch)
+	for _, call := range calls {
+		if err != nil {
+			call.err = err
+		}
+		close(call.done)
+	}
+}
+
+func (t *interceptorTransport) roundTrip(req *http.Request, calls []*pendingCall, batch bool) error {
+	msgs := make([]*jsonrpcMessage, len(calls))
+	for i, call := range calls {
+		msgs[i] = &jsonrpcMessage{
+			Version: "2.0",
+			ID:      json.RawMessage(strconv.Itoa(i + 1)),
+			Method:  call.call.Method,
+			Params:  call.call.Params,
+		}
+	}
+
+	// request không phải batch dùng context của lời gọi để giữ timeout và tracing của interceptor
+	ctx := req.Context()
+	var body []byte
+	var err error
+	if batch || len(msgs) > 1 {
+		batch = true
+		body, err = json.Marshal(msgs)
+	} else {
+		ctx = calls[0].ctx
+		body, err = json.Marshal(msgs[0])
+	}
+	if err != nil {
+		return errors.Wrap(err, "encode json-rpc request error")
+	}
+
+	out := req.Clone(ctx)
+	out.Body = ioutil.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	out.GetBody = nil
+
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "read response body error")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return rpc.HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       data,
+		}
+	}
+
+	var responses []*jsonrpcMessage
+	if batch {
+		err = json.Unmarshal(data, &responses)
+	} else {
+		response := new(jsonrpcMessage)
+		err = json.Unmarshal(data, response)
+		responses = []*jsonrpcMessage{response}
+	}
+	if err != nil {
+		return errors.Wrap(err, "decode json-rpc response error")
+	}
+
+	for _, response := range responses {
+		index, err := strconv.Atoi(string(response.ID))
+		if err != nil || index < 1 || index > len(calls) {
+			continue
+		}
+		call := calls[index-1]
+		call.answered = true
+		if response.Error != nil {
+			call.err = response.Error
+			continue
+		}
+		call.call.Result = response.Result
+	}
+	for _, call := range calls {
+		if !call.answered {
+			call.err = errors.Errorf("missing json-rpc response for %s", call.call.Method)
+		}
+	}
+	return nil
+}
+
+type pendingCall struct {
+	ctx      context.Context
+	call     *RPCCall
+	answered bool
+	err      error
+	done     chan struct{}
+}
+
+// callCollector gom các lời gọi của cùng một request http tới được cuối chuỗi interceptor
+// request được gửi khi mọi lời gọi chưa kết thúc đều đang đợi kết quả từ node
+type callCollector struct {
+	send func(calls []*pendingCall)
+
+	mu      sync.Mutex
+	active  int
+	pending []*pendingCall
+}
+
+// invoke là Invoker cuối chuỗi, đợi tới khi request chứa `call` được gửi và có kết quả
+func (c *callCollector) invoke(ctx context.Context, call *RPCCall) error {
+	pending := &pendingCall{
+		ctx:  ctx,
+		call: call,
+		done: make(chan struct{}),
+	}
+
+	c.mu.Lock()
+	c.pending = append(c.pending, pending)
+	c.flush()
+
+	<-pending.done
+	return pending.err
+}
+
+// done đánh dấu một lời gọi đã đi hết chuỗi interceptor
+func (c *callCollector) done() {
+	c.mu.Lock()
+	c.active--
+	c.flush()
+}
+
+// flush gửi các lời gọi đang đợi nếu không còn lời gọi nào khác có thể tham gia, mở khoá `mu`
+func (c *callCollector) flush() {
+	if len(c.pending) == 0 || len(c.pending) < c.active {
+		c.mu.Unlock()
+		return
+	}
+
+	calls := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+	c.send(calls)
+}
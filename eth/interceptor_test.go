@@ -0,0 +1,158 @@
+package eth_test
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInterceptorsOrder(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		trace []string
+	)
+	record := func(name string) eth.Interceptor {
+		return func(ctx context.Context, call *eth.RPCCall, invoker eth.Invoker) error {
+			mu.Lock()
+			trace = append(trace, name+" before "+call.Method)
+			mu.Unlock()
+			err := invoker(ctx, call)
+			mu.Lock()
+			trace = append(trace, name+" after "+string(call.Result))
+			mu.Unlock()
+			return err
+		}
+	}
+
+	client, node := ethtest.NewClient(t, eth.WithInterceptors(record("outer"), record("inner")))
+	node.SetHead(5)
+	var head hexutil.Uint64
+	if err := client.RpcClient().CallContext(ethtest.Context(t), &head, "eth_blockNumber"); err != nil {
+		t.Fatalf("eth_blockNumber: %v", err)
+	}
+
+	want := []string{
+		"outer before eth_blockNumber",
+		"inner before eth_blockNumber",
+		`inner after "0x5"`,
+		`outer after "0x5"`,
+	}
+	if !reflect.DeepEqual(trace, want) {
+		t.Fatalf("trace = %q, want %q", trace, want)
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	tests := []struct {
+		name        string
+		interceptor eth.Interceptor
+		wantHead    uint64
+		wantErr     string
+	}{
+		{
+			name: "cached result",
+			interceptor: func(ctx context.Context, call *eth.RPCCall, invoker eth.Invoker) error {
+				call.Result = json.RawMessage(`"0x2a"`)
+				return nil
+			},
+			wantHead: 42,
+		},
+		{
+			name: "simulated node error",
+			interceptor: func(ctx context.Context, call *eth.RPCCall, invoker eth.Invoker) error {
+				return &eth.RPCError{Code: -32000, Message: "header not found"}
+			},
+			wantErr: "header not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, node := ethtest.NewClient(t, eth.WithInterceptors(test.interceptor))
+			var head hexutil.Uint64
+			err := client.RpcClient().CallContext(ethtest.Context(t), &head, "eth_blockNumber")
+			if test.wantErr != "" {
+				var rpcErr rpc.Error
+				if !stderrors.As(err, &rpcErr) || rpcErr.Error() != test.wantErr {
+					t.Fatalf("eth_blockNumber error = %v, want json-rpc error %q", err, test.wantErr)
+				}
+			} else if err != nil || uint64(head) != test.wantHead {
+				t.Fatalf("eth_blockNumber = %d, %v; want %d", head, err, test.wantHead)
+			}
+			if count := node.RequestCount("eth_blockNumber"); count != 0 {
+				t.Fatalf("node received %d eth_blockNumber calls, want none", count)
+			}
+		})
+	}
+}
+
+func TestInterceptorsKeepBatch(t *testing.T) {
+	tests := []struct {
+		name string
+		// delay là thời gian interceptor giữ lời gọi `eth_chainId` trước khi gọi tiếp
+		delay time.Duration
+		// skip là true nếu interceptor tự trả lời `eth_chainId`
+		skip bool
+		want []string
+	}{
+		{name: "all calls", want: []string{"eth_blockNumber", "eth_chainId", "eth_gasPrice"}},
+		{name: "slow interceptor", delay: 20 * time.Millisecond, want: []string{"eth_blockNumber", "eth_chainId", "eth_gasPrice"}},
+		{name: "short circuited call", skip: true, want: []string{"eth_blockNumber", "eth_gasPrice"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			interceptor := func(ctx context.Context, call *eth.RPCCall, invoker eth.Invoker) error {
+				if call.Method == "eth_chainId" {
+					if test.skip {
+						call.Result = json.RawMessage(`"0x1"`)
+						return nil
+					}
+					time.Sleep(test.delay)
+				}
+				return invoker(ctx, call)
+			}
+			client, node := ethtest.NewClient(t, eth.WithInterceptors(interceptor))
+			node.SetResult("eth_gasPrice", (*hexutil.Big)(hexutil.MustDecodeBig("0x5")))
+
+			var head, chainID, gasPrice hexutil.Big
+			batch := []rpc.BatchElem{
+				{Method: "eth_blockNumber", Result: &head},
+				{Method: "eth_chainId", Result: &chainID},
+				{Method: "eth_gasPrice", Result: &gasPrice},
+			}
+			if err := client.RpcClient().BatchCallContext(ethtest.Context(t), batch); err != nil {
+				t.Fatalf("BatchCallContext: %v", err)
+			}
+			for _, elem := range batch {
+				if elem.Error != nil {
+					t.Fatalf("%s error: %v", elem.Method, elem.Error)
+				}
+			}
+
+			// các lời gọi tới được node vẫn được gửi trong một request HTTP
+			requests := node.Requests()
+			methods := make([]string, len(requests))
+			for i, request := range requests {
+				methods[i] = request.Method
+				if request.HTTPRequest != requests[0].HTTPRequest {
+					t.Fatalf("batch was split into several http requests: %+v", requests)
+				}
+			}
+			// các lời gọi đi qua interceptor song song nên thứ tự trong batch không cố định
+			sort.Strings(methods)
+			if !reflect.DeepEqual(methods, test.want) {
+				t.Fatalf("node received %v, want %v", methods, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// Multicall3Address là địa chỉ của contract Multicall3, giống nhau trên bsc mainnet, testnet và hầu hết các chain EVM
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// DefaultMulticallBatchSize là số lời gọi tối đa trong một `eth_call` tới Multicall3
+const DefaultMulticallBatchSize = 300
+
+const multicall3ABIJson = `[
+    {
+      "inputs": [
+        {
+          "components": [
+            {
+              "internalType": "address",
+              "name": "target",
+              "type": "address"
+            },
+            {
+              "internalType": "bool",
+              "name": "allowFailure",
+              "type": "bool"
+            },
+            {
+              "internalType": "bytes",
+              "name": "callData",
+              "type": "bytes"
+            }
+          ],
+          "internalType": "struct Multicall3.Call3[]",
+          "name": "calls",
+          "type": "tuple[]"
+        }
+      ],
+      "name": "aggregate3",
+      "outputs": [
+        {
+          "components": [
+            {
+              "internalType": "bool",
+              "name": "success",
+              "type": "bool"
+            },
+            {
+              "internalType": "bytes",
+              "name": "returnData",
+              "type": "bytes"
+            }
+          ],
+          "internalType": "struct Multicall3.Result[]",
+          "name": "returnData",
+          "type": "tuple[]"
+        }
+      ],
+      "stateMutability": "payable",
+      "type": "function"
+    }
+  ]`
+
+var Multicall3ABI abi.ABI
+
+func init() {
+	var err error
+	Multicall3ABI, err = abi.JSON(strings.NewReader(multicall3ABIJson))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ViewCall là một lời gọi hàm view `Function` của contract `Address` với abi `ABI`
+// sau khi gọi, nếu thành công giá trị trả về được unpack vào `Result`, ngược lại lỗi được đặt vào `Err`
+type ViewCall struct {
+	ABI      abi.ABI
+	Address  common.Address
+	Function string
+	Args     []interface{}
+
+	// Result phải là pointer
+	Result interface{}
+
+	// Err là lỗi của riêng lời gọi này, RevertError nếu hàm bị revert
+	Err error
+}
+
+// NewViewCall tạo ViewCall, giá trị trả về được unpack vào `result`
+func NewViewCall(abi abi.ABI, address common.Address, result interface{}, function string, args ...interface{}) *ViewCall {
+	return &ViewCall{
+		ABI:      abi,
+		Address:  address,
+		Function: function,
+		Args:     args,
+		Result:   result,
+	}
+}
+
+// Multicall gộp nhiều lời gọi hàm view thành một `eth_call` tới contract Multicall3
+type Multicall struct {
+	client    *Client
+	address   common.Address
+	batchSize int
+}
+
+// Multicall trả về Multicall dùng contract Multicall3 của client
+func (c *Client) Multicall() *Multicall {
+	return &Multicall{
+		client:    c,
+		address:   c.multicallAddress,
+		batchSize: DefaultMulticallBatchSize,
+	}
+}
+
+// WithBatchSize trả về Multicall gộp tối đa `batchSize` lời gọi trong một `eth_call`
+func (m *Multicall) WithBatchSize(batchSize int) *Multicall {
+	return &Multicall{
+		client:    m.client,
+		address:   m.address,
+		batchSize: batchSize,
+	}
+}
+
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// Call thực hiện tất cả `calls`, chia thành nhiều `eth_call` nếu số lời gọi lớn hơn batch size
+// lỗi trả về là lỗi của cả lần gọi, lỗi của từng lời gọi nằm trong ViewCall.Err
+// trạng thái được đọc tại block đặt bằng ContextAtBlock giống CallContractViewFunction
+func (m *Multicall) Call(ctx context.Context, calls []*ViewCall) error {
+	batchSize := m.batchSize
+	if batchSize <= 0 {
+		batchSize = len(calls)
+	}
+
+	for start := 0; start < len(calls); start += batchSize {
+		end := start + batchSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+		if err := m.call(ctx, calls[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Multicall) call(ctx context.Context, calls []*ViewCall) error {
+	args := make([]multicall3Call, 0, len(calls))
+	packed := make([]*ViewCall, 0, len(calls))
+	for _, call := range calls {
+		data, err := call.ABI.Pack(call.Function, call.Args...)
+		if err != nil {
+			call.Err = errors.Wrap(err, "multicall abi pack error")
+			continue
+		}
+		args = append(args, multicall3Call{
+			Target:       call.Address,
+			AllowFailure: true,
+			CallData:     data,
+		})
+		packed = append(packed, call)
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	var result struct {
+		ReturnData []struct {
+			Success    bool
+			ReturnData []byte
+		}
+	}
+	err := m.client.CallContractViewFunction(ctx, Multicall3ABI, m.address, &result, "aggregate3", args)
+	if err != nil {
+		return errors.Wrap(err, "multicall aggregate3 error")
+	}
+	if len(result.ReturnData) != len(packed) {
+		return errors.Errorf("multicall returned %d results for %d calls", len(result.ReturnData), len(packed))
+	}
+
+	for i, call := range packed {
+		returnData := result.ReturnData[i]
+		if !returnData.Success {
+			call.Err = DecodeRevert(&call.ABI, returnData.ReturnData)
+			continue
+		}
+		if err = call.ABI.UnpackIntoInterface(call.Result, call.Function, returnData.ReturnData); err != nil {
+			call.Err = errors.Wrap(err, "unpack result error")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package eth_test
+
+import (
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"testing"
+)
+
+var bobCounter = common.HexToAddress("0x00000000000000000000000000000000000000c1")
+
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// countCall là lời gọi `count(account)` của counterABI tới `address` và kết quả giả lập
+type countCall struct {
+	address common.Address
+	account common.Address
+	// count là kết quả, nil nếu lời gọi bị revert
+	count *big.Int
+}
+
+func (c countCall) call3(t *testing.T) multicall3Call {
+	data, err := counterABI.Pack("count", c.account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return multicall3Call{Target: c.address, AllowFailure: true, CallData: data}
+}
+
+func (c countCall) result3(t *testing.T) multicall3Result {
+	if c.count == nil {
+		stringType, _ := abi.NewType("string", "", nil)
+		encoded, err := abi.Arguments{{Type: stringType}}.Pack("paused")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return multicall3Result{ReturnData: append([]byte{0x08, 0xc3, 0x79, 0xa0}, encoded...)}
+	}
+	data, err := counterABI.Methods["count"].Outputs.Pack(c.count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return multicall3Result{Success: true, ReturnData: data}
+}
+
+func TestMulticallAggregate(t *testing.T) {
+	tests := []struct {
+		name      string
+		calls     []countCall
+		batchSize int
+		// batches là các nhóm chỉ số lời gọi trong từng `eth_call` tới Multicall3
+		batches [][]int
+	}{
+		{
+			name:    "single batch",
+			calls:   []countCall{{counterAddress, alice, big.NewInt(1)}, {bobCounter, bob, big.NewInt(2)}},
+			batches: [][]int{{0, 1}},
+		},
+		{
+			name:    "failed call",
+			calls:   []countCall{{counterAddress, alice, big.NewInt(1)}, {bobCounter, bob, nil}, {counterAddress, bob, big.NewInt(3)}},
+			batches: [][]int{{0, 1, 2}},
+		},
+		{
+			name:      "split by batch size",
+			calls:     []countCall{{counterAddress, alice, big.NewInt(1)}, {bobCounter, bob, big.NewInt(2)}, {counterAddress, bob, big.NewInt(3)}},
+			batchSize: 2,
+			batches:   [][]int{{0, 1}, {2}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, node := ethtest.NewClient(t)
+			for _, batch := range test.batches {
+				calls := make([]multicall3Call, len(batch))
+				results := make([]multicall3Result, len(batch))
+				for i, index := range batch {
+					calls[i] = test.calls[index].call3(t)
+					results[i] = test.calls[index].result3(t)
+				}
+				node.OnCall(eth.Multicall3Address, eth.Multicall3ABI, "aggregate3", []interface{}{calls}, results)
+			}
+
+			results := make([]struct{ Count *big.Int }, len(test.calls))
+			calls := make([]*eth.ViewCall, len(test.calls))
+			for i, call := range test.calls {
+				calls[i] = eth.NewViewCall(counterABI, call.address, &results[i], "count", call.account)
+			}
+			multicall := client.Multicall()
+			if test.batchSize > 0 {
+				multicall = multicall.WithBatchSize(test.batchSize)
+			}
+			if err := multicall.Call(ethtest.Context(t), calls); err != nil {
+				t.Fatalf("Multicall: %v", err)
+			}
+
+			if count := node.RequestCount("eth_call"); count != len(test.batches) {
+				t.Fatalf("eth_call count = %d, want %d", count, len(test.batches))
+			}
+			for i, call := range test.calls {
+				if call.count == nil {
+					revertErr, ok := calls[i].Err.(*eth.RevertError)
+					if !ok || revertErr.Reason != "paused" {
+						t.Fatalf("call %d error = %v, want revert paused", i, calls[i].Err)
+					}
+					continue
+				}
+				if calls[i].Err != nil {
+					t.Fatalf("call %d error: %v", i, calls[i].Err)
+				}
+				if results[i].Count.Cmp(call.count) != 0 {
+					t.Fatalf("call %d count = %s, want %s", i, results[i].Count, call.count)
+				}
+			}
+		})
+	}
+}
+
+func TestMulticallPackError(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	call := countCall{counterAddress, alice, big.NewInt(4)}
+	node.OnCall(eth.Multicall3Address, eth.Multicall3ABI, "aggregate3",
+		[]interface{}{[]multicall3Call{call.call3(t)}}, []multicall3Result{call.result3(t)})
+
+	var valid, invalid struct{ Count *big.Int }
+	calls := []*eth.ViewCall{
+		eth.NewViewCall(counterABI, counterAddress, &invalid, "count", "not an address"),
+		eth.NewViewCall(counterABI, counterAddress, &valid, "count", alice),
+	}
+	if err := client.Multicall().Call(ethtest.Context(t), calls); err != nil {
+		t.Fatalf("Multicall: %v", err)
+	}
+	if calls[0].Err == nil {
+		t.Fatal("call with invalid arguments has no error")
+	}
+	if calls[1].Err != nil || valid.Count.Int64() != 4 {
+		t.Fatalf("valid call = %v, %v; want 4", valid.Count, calls[1].Err)
+	}
+}
+
+func TestMulticallResultCountMismatch(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	first := countCall{counterAddress, alice, big.NewInt(1)}
+	second := countCall{bobCounter, bob, big.NewInt(2)}
+	node.OnCall(eth.Multicall3Address, eth.Multicall3ABI, "aggregate3",
+		[]interface{}{[]multicall3Call{first.call3(t), second.call3(t)}}, []multicall3Result{first.result3(t)})
+
+	var results [2]struct{ Count *big.Int }
+	calls := []*eth.ViewCall{
+		eth.NewViewCall(counterABI, first.address, &results[0], "count", first.account),
+		eth.NewViewCall(counterABI, second.address, &results[1], "count", second.account),
+	}
+	if err := client.Multicall().Call(ethtest.Context(t), calls); err == nil {
+		t.Fatal("Multicall accepted fewer results than calls")
+	}
+}
@@ -0,0 +1,296 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NonceBackend là các hàm của node mà NonceManager cần để đồng bộ nonce
+type NonceBackend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+}
+
+// NonceManager cấp nonce cho các transaction gửi từ nhiều goroutine trên cùng một ví
+// nonce được cấp ở local, chỉ đồng bộ lại với node khi cần
+type NonceManager struct {
+	backend NonceBackend
+
+	// mu chỉ giữ trong lúc đọc/ghi trạng thái nonce, không giữ trong lúc gọi node
+	mu       sync.Mutex
+	accounts map[common.Address]*accountNonces
+}
+
+type accountNonces struct {
+	// syncMu giữ trong lúc đồng bộ với node để các lần đồng bộ của cùng một ví không chạy song song,
+	// không chặn các ví khác và việc cấp nonce đã đồng bộ
+	syncMu sync.Mutex
+	synced bool
+	next   uint64
+
+	// reserved là các nonce đã cấp nhưng chưa gửi xong
+	reserved map[uint64]struct{}
+	// sent là các nonce đã gửi lên node cùng hash của transaction
+	sent map[uint64]common.Hash
+	// released là các nonce đã cấp nhưng transaction không được gửi, sẽ được cấp lại trước
+	released map[uint64]struct{}
+}
+
+func NewNonceManager(backend NonceBackend) *NonceManager {
+	return &NonceManager{
+		backend:  backend,
+		accounts: map[common.Address]*accountNonces{},
+	}
+}
+
+func (m *NonceManager) account(addr common.Address) *accountNonces {
+	a, ok := m.accounts[addr]
+	if !ok {
+		a = &accountNonces{
+			reserved: map[uint64]struct{}{},
+			sent:     map[uint64]common.Hash{},
+			released: map[uint64]struct{}{},
+		}
+		m.accounts[addr] = a
+	}
+	return a
+}
+
+// Next cấp nonce tiếp theo cho `account`
+// các nonce đã bị trả lại bằng Release được cấp lại trước để không để lại khoảng trống
+func (m *NonceManager) Next(ctx context.Context, account common.Address) (uint64, error) {
+	m.mu.Lock()
+	a := m.account(account)
+	synced := a.synced
+	m.mu.Unlock()
+
+	if !synced {
+		if err := m.resync(ctx, account, a, false); err != nil {
+			return 0, err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nonce := a.next
+	if len(a.released) > 0 {
+		nonce = lowestNonce(a.released)
+		delete(a.released, nonce)
+	} else {
+		a.next++
+	}
+	a.reserved[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// Sent đánh dấu `nonce` đã được dùng cho transaction `txHash`
+func (m *NonceManager) Sent(account common.Address, nonce uint64, txHash common.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a := m.account(account)
+	delete(a.reserved, nonce)
+	a.sent[nonce] = txHash
+}
+
+// sentTransaction trả về ví gửi và nonce của transaction `txHash` đã gửi qua NonceManager
+func (m *NonceManager) sentTransaction(txHash common.Hash) (common.Address, uint64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for account, a := range m.accounts {
+		for nonce, hash := range a.sent {
+			if hash == txHash {
+				return account, nonce, true
+			}
+		}
+	}
+	return common.Address{}, 0, false
+}
+
+// Release trả lại `nonce` khi transaction không được gửi lên node
+func (m *NonceManager) Release(account common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a := m.account(account)
+	delete(a.reserved, nonce)
+	if nonce < a.next {
+		a.released[nonce] = struct{}{}
+	}
+}
+
+// claim giữ `nonce` để lấp khoảng trống, trả về false nếu nonce đang được dùng
+func (m *NonceManager) claim(account common.Address, nonce uint64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a := m.account(account)
+	if _, ok := a.reserved[nonce]; ok || nonce >= a.next {
+		return false
+	}
+	delete(a.released, nonce)
+	a.reserved[nonce] = struct{}{}
+	return true
+}
+
+// Resync đồng bộ lại nonce của `account` theo pending nonce trên node
+func (m *NonceManager) Resync(ctx context.Context, account common.Address) error {
+	m.mu.Lock()
+	a := m.account(account)
+	m.mu.Unlock()
+
+	return m.resync(ctx, account, a, true)
+}
+
+// resync lấy pending nonce mà không giữ m.mu, `force` là false thì bỏ qua nếu goroutine khác vừa đồng bộ xong
+func (m *NonceManager) resync(ctx context.Context, account common.Address, a *accountNonces, force bool) error {
+	a.syncMu.Lock()
+	defer a.syncMu.Unlock()
+
+	m.mu.Lock()
+	if a.synced && !force {
+		m.mu.Unlock()
+		return nil
+	}
+	sent := make(map[uint64]common.Hash, len(a.sent))
+	for nonce, txHash := range a.sent {
+		sent[nonce] = txHash
+	}
+	m.mu.Unlock()
+
+	pending, err := m.backend.PendingNonceAt(ctx, account)
+	if err != nil {
+		return errors.Wrap(err, "nonce manager get pending nonce error")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// nonce đang được goroutine khác dùng thì không cấp lại,
+	// kể cả transaction gửi trong lúc lấy pending nonce vì node có thể chưa tính tới
+	next := pending
+	for nonce := range a.reserved {
+		if nonce >= next {
+			next = nonce + 1
+		}
+	}
+	for nonce, txHash := range a.sent {
+		if sent[nonce] != txHash && nonce >= next {
+			next = nonce + 1
+		}
+	}
+
+	for nonce := range a.released {
+		if nonce < pending || nonce >= next {
+			delete(a.released, nonce)
+		}
+	}
+	for nonce := range a.sent {
+		if nonce < pending {
+			delete(a.sent, nonce)
+		}
+	}
+
+	a.next = next
+	a.synced = true
+	return nil
+}
+
+// Gaps trả về các nonce của `account` nằm giữa pending nonce trên node và nonce tiếp theo ở local
+// mà node không có transaction nào, do transaction bị drop khỏi mempool hoặc không được gửi
+// các transaction có nonce lớn hơn khoảng trống sẽ bị kẹt cho tới khi khoảng trống được lấp
+func (m *NonceManager) Gaps(ctx context.Context, account common.Address) ([]uint64, error) {
+	m.mu.Lock()
+	a := m.account(account)
+	synced := a.synced
+	m.mu.Unlock()
+	if !synced {
+		return nil, nil
+	}
+
+	pending, err := m.backend.PendingNonceAt(ctx, account)
+	if err != nil {
+		return nil, errors.Wrap(err, "nonce manager get pending nonce error")
+	}
+
+	m.mu.Lock()
+	gaps := make([]uint64, 0)
+	sent := map[uint64]common.Hash{}
+	for nonce := pending; nonce < a.next; nonce++ {
+		if _, ok := a.reserved[nonce]; ok {
+			continue
+		}
+		if txHash, ok := a.sent[nonce]; ok {
+			sent[nonce] = txHash
+			continue
+		}
+		gaps = append(gaps, nonce)
+	}
+	m.mu.Unlock()
+
+	dropped := make([]uint64, 0)
+	for nonce, txHash := range sent {
+		_, _, err = m.backend.TransactionByHash(ctx, txHash)
+		if err == nil {
+			continue
+		}
+		if err != ethereum.NotFound {
+			return nil, errors.Wrap(err, "nonce manager get transaction error")
+		}
+		dropped = append(dropped, nonce)
+	}
+
+	// trong lúc gọi node nonce có thể đã được gửi lại bằng transaction khác
+	m.mu.Lock()
+	for _, nonce := range dropped {
+		if txHash, ok := a.sent[nonce]; ok && txHash == sent[nonce] {
+			delete(a.sent, nonce)
+			gaps = append(gaps, nonce)
+		}
+	}
+	m.mu.Unlock()
+
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	return gaps, nil
+}
+
+func lowestNonce(nonces map[uint64]struct{}) uint64 {
+	first := true
+	var lowest uint64
+	for nonce := range nonces {
+		if first || nonce < lowest {
+			lowest = nonce
+			first = false
+		}
+	}
+	return lowest
+}
+
+// IsNonceError kiểm tra lỗi node trả về có phải do nonce đã bị transaction khác dùng hay không
+func IsNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(errors.Cause(err).Error())
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
+
+// IsKnownTransactionError kiểm tra lỗi node trả về có phải do chính transaction đã ký này đã nằm trong mempool hay không,
+// ví dụ khi request gửi transaction được gửi lại sau lỗi đường truyền hoặc khi chuyển endpoint
+func IsKnownTransactionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(errors.Cause(err).Error())
+	return strings.Contains(msg, "already known") ||
+		strings.Contains(msg, "known transaction")
+}
@@ -0,0 +1,278 @@
+package eth_test
+
+import (
+	"context"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var bob = common.HexToAddress("0x00000000000000000000000000000000000000b0")
+
+// nonceBackend là node giả chỉ có pending nonce và các transaction trong mempool
+type nonceBackend struct {
+	mu      sync.Mutex
+	pending map[common.Address]uint64
+	known   map[common.Hash]bool
+	// block giữ PendingNonceAt của các ví trong map cho tới khi channel được đóng
+	block map[common.Address]chan struct{}
+	// blocked nhận ví mỗi khi PendingNonceAt bắt đầu chờ
+	blocked chan common.Address
+}
+
+func newNonceBackend() *nonceBackend {
+	return &nonceBackend{
+		pending: map[common.Address]uint64{},
+		known:   map[common.Hash]bool{},
+		block:   map[common.Address]chan struct{}{},
+		blocked: make(chan common.Address, 1),
+	}
+}
+
+func (b *nonceBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	b.mu.Lock()
+	block := b.block[account]
+	b.mu.Unlock()
+	if block != nil {
+		b.blocked <- account
+		select {
+		case <-block:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pending[account], nil
+}
+
+func (b *nonceBackend) TransactionByHash(_ context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.known[hash] {
+		return nil, false, ethereum.NotFound
+	}
+	return nil, true, nil
+}
+
+func (b *nonceBackend) send(hash common.Hash) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.known[hash] = true
+}
+
+func txHash(account common.Address, nonce uint64) common.Hash {
+	return common.BytesToHash(append(account.Bytes(), byte(nonce)))
+}
+
+func TestNonceManagerParallelSenders(t *testing.T) {
+	backend := newNonceBackend()
+	backend.pending[alice] = 10
+	nonces := eth.NewNonceManager(backend)
+	ctx := ethtest.Context(t)
+
+	const senders = 40
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		sent     = map[uint64]bool{}
+		released = map[uint64]bool{}
+	)
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			nonce, err := nonces.Next(ctx, alice)
+			if err != nil {
+				t.Errorf("Next: %v", err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if sent[nonce] {
+				t.Errorf("nonce %d issued twice", nonce)
+			}
+			// nonce đã trả lại được cấp lại cho sender khác
+			delete(released, nonce)
+			// một phần transaction không gửi được và trả lại nonce
+			if i%4 == 0 {
+				nonces.Release(alice, nonce)
+				released[nonce] = true
+				return
+			}
+			hash := txHash(alice, nonce)
+			backend.send(hash)
+			nonces.Sent(alice, nonce, hash)
+			sent[nonce] = true
+		}(i)
+	}
+	wg.Wait()
+
+	if len(sent) != senders-senders/4 {
+		t.Fatalf("sent %d transactions, want %d", len(sent), senders-senders/4)
+	}
+	issued := uint64(len(sent) + len(released))
+	for nonce := uint64(10); nonce < 10+issued; nonce++ {
+		if !sent[nonce] && !released[nonce] {
+			t.Fatalf("nonce %d was skipped", nonce)
+		}
+	}
+
+	// nonce đã trả lại là khoảng trống và được cấp lại trước nonce mới
+	gaps, err := nonces.Gaps(ctx, alice)
+	if err != nil {
+		t.Fatalf("Gaps: %v", err)
+	}
+	if len(gaps) != len(released) {
+		t.Fatalf("Gaps = %v, want the %d released nonces", gaps, len(released))
+	}
+	for i := 0; i < len(released); i++ {
+		nonce, err := nonces.Next(ctx, alice)
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if nonce != gaps[i] {
+			t.Fatalf("Next = %d, want released nonce %d", nonce, gaps[i])
+		}
+	}
+	nonce, err := nonces.Next(ctx, alice)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if nonce != 10+issued {
+		t.Fatalf("Next = %d, want %d", nonce, 10+issued)
+	}
+}
+
+func TestNonceManagerGaps(t *testing.T) {
+	tests := []struct {
+		name string
+		// dropped là các nonce đã gửi nhưng transaction không còn trên node
+		dropped  []uint64
+		released []uint64
+		reserved []uint64
+		want     []uint64
+	}{
+		{name: "no gaps", want: []uint64{}},
+		{name: "dropped transaction", dropped: []uint64{2}, want: []uint64{2}},
+		{name: "released nonce", released: []uint64{1, 3}, want: []uint64{1, 3}},
+		{name: "reserved nonce is not a gap", reserved: []uint64{1}, dropped: []uint64{3}, want: []uint64{3}},
+		{name: "sorted", dropped: []uint64{4, 0}, released: []uint64{2}, want: []uint64{0, 2, 4}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend := newNonceBackend()
+			nonces := eth.NewNonceManager(backend)
+			ctx := ethtest.Context(t)
+
+			contains := func(nonces []uint64, nonce uint64) bool {
+				for _, n := range nonces {
+					if n == nonce {
+						return true
+					}
+				}
+				return false
+			}
+			for i := 0; i < 5; i++ {
+				if _, err := nonces.Next(ctx, alice); err != nil {
+					t.Fatalf("Next: %v", err)
+				}
+			}
+			for nonce := uint64(0); nonce < 5; nonce++ {
+				switch {
+				case contains(test.reserved, nonce):
+				case contains(test.released, nonce):
+					nonces.Release(alice, nonce)
+				default:
+					hash := txHash(alice, nonce)
+					if !contains(test.dropped, nonce) {
+						backend.send(hash)
+					}
+					nonces.Sent(alice, nonce, hash)
+				}
+			}
+
+			gaps, err := nonces.Gaps(ctx, alice)
+			if err != nil {
+				t.Fatalf("Gaps: %v", err)
+			}
+			if !reflect.DeepEqual(gaps, test.want) {
+				t.Fatalf("Gaps = %v, want %v", gaps, test.want)
+			}
+		})
+	}
+}
+
+func TestNonceManagerSyncDoesNotBlockOtherAccounts(t *testing.T) {
+	backend := newNonceBackend()
+	backend.pending[bob] = 3
+	block := make(chan struct{})
+	backend.block[alice] = block
+	nonces := eth.NewNonceManager(backend)
+	ctx := ethtest.Context(t)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := nonces.Next(ctx, alice)
+		done <- err
+	}()
+	<-backend.blocked
+
+	// alice đang chờ node trả pending nonce, bob vẫn được cấp nonce
+	nonce, err := nonces.Next(ctx, bob)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if nonce != 3 {
+		t.Fatalf("Next = %d, want 3", nonce)
+	}
+	nonces.Sent(bob, nonce, txHash(bob, nonce))
+
+	close(block)
+	if err = <-done; err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+}
+
+func TestNonceManagerResyncKeepsNoncesSentDuringSync(t *testing.T) {
+	backend := newNonceBackend()
+	nonces := eth.NewNonceManager(backend)
+	ctx := ethtest.Context(t)
+
+	nonce, err := nonces.Next(ctx, alice)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+
+	// node chưa thấy transaction gửi trong lúc đang lấy pending nonce
+	block := make(chan struct{})
+	backend.mu.Lock()
+	backend.block[alice] = block
+	backend.mu.Unlock()
+	done := make(chan error, 1)
+	go func() {
+		done <- nonces.Resync(ctx, alice)
+	}()
+	<-backend.blocked
+	nonces.Sent(alice, nonce, txHash(alice, nonce))
+	close(block)
+	if err = <-done; err != nil {
+		t.Fatalf("Resync: %v", err)
+	}
+
+	next, err := nonces.Next(ctx, alice)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if next == nonce {
+		t.Fatalf("Next reissued nonce %d that was sent during resync", nonce)
+	}
+}
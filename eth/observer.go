@@ -0,0 +1,33 @@
+package eth
+
+// LogObserver nhận độ trễ và các log mà Watcher, Scanner và Stream của client chuyển cho người gọi, dùng cho metrics
+// mỗi log chỉ được báo một lần kể cả khi Stream đọc log qua Scanner hoặc Watcher bên trong
+type LogObserver interface {
+	// ObserveFilterLag báo block mới nhất `head` và block cuối cùng đã chuyển đủ log `processed` của Watcher hoặc Stream `filter`
+	ObserveFilterLag(filter string, head, processed uint64)
+
+	// ObserveLogs báo các log vừa được chuyển cho người gọi
+	ObserveLogs(changes []*FilterChange)
+}
+
+// WithLogObserver đặt LogObserver nhận độ trễ và log của Watcher, Scanner và Stream
+// Watcher đọc thêm block mới nhất mỗi lần Poll để tính độ trễ
+func WithLogObserver(observer LogObserver) Option {
+	return func(c *Client) {
+		c.logObserver = observer
+	}
+}
+
+// observeLogs báo `changes` cho LogObserver nếu có
+func (c *Client) observeLogs(changes []*FilterChange) {
+	if c.logObserver != nil && len(changes) > 0 {
+		c.logObserver.ObserveLogs(changes)
+	}
+}
+
+// observeFilterLag báo độ trễ của `filter` cho LogObserver nếu có
+func (c *Client) observeFilterLag(filter string, head, processed uint64) {
+	if c.logObserver != nil {
+		c.logObserver.ObserveFilterLag(filter, head, processed)
+	}
+}
@@ -0,0 +1,118 @@
+package eth
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// rateLimiter giới hạn số request mỗi giây bằng token bucket và số request đang chạy cùng lúc của một endpoint
+// rateLimiter nil không giới hạn gì
+type rateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	inFlight chan struct{}
+}
+
+// newRateLimiter tạo rateLimiter cho phép `rate` request mỗi giây, tối đa `burst` request liền nhau
+// và tối đa `maxInFlight` request cùng lúc. `rate` hoặc `maxInFlight` bằng 0 là không giới hạn
+// trả về nil nếu không có giới hạn nào
+func newRateLimiter(rate float64, burst int, maxInFlight int) *rateLimiter {
+	if rate <= 0 && maxInFlight <= 0 {
+		return nil
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+	l := &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+	if maxInFlight > 0 {
+		l.inFlight = make(chan struct{}, maxInFlight)
+	}
+	return l
+}
+
+// acquire đợi tới khi được gửi request hoặc `ctx` bị huỷ
+// `release` phải được gọi khi request kết thúc để trả lại chỗ cho request khác
+func (l *rateLimiter) acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			if l.inFlight != nil {
+				<-l.inFlight
+			}
+		})
+	}
+	if err = l.wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// wait lấy một token từ bucket, đợi nếu bucket đang rỗng
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// releaseOnClose trả lại chỗ cho rateLimiter khi người gọi đọc xong và đóng response body
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,181 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/rpc"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	tests := []struct {
+		name        string
+		rate        float64
+		burst       int
+		maxInFlight int
+		wantNil     bool
+		wantBurst   float64
+	}{
+		{name: "no limit", wantNil: true},
+		{name: "negative rate", rate: -1, wantNil: true},
+		{name: "rate", rate: 10, burst: 5, wantBurst: 5},
+		{name: "burst at least one", rate: 10, wantBurst: 1},
+		{name: "in flight only", maxInFlight: 2, wantBurst: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := newRateLimiter(test.rate, test.burst, test.maxInFlight)
+			if test.wantNil {
+				if l != nil {
+					t.Fatalf("newRateLimiter = %+v, want nil", l)
+				}
+				return
+			}
+			if l.burst != test.wantBurst || l.tokens != test.wantBurst {
+				t.Fatalf("burst = %v, tokens = %v, want %v", l.burst, l.tokens, test.wantBurst)
+			}
+			if (l.inFlight != nil) != (test.maxInFlight > 0) {
+				t.Fatalf("in flight limit = %v, want %v", l.inFlight != nil, test.maxInFlight > 0)
+			}
+		})
+	}
+}
+
+func TestRateLimiterNil(t *testing.T) {
+	var l *rateLimiter
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	release()
+}
+
+func TestRateLimiterRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     float64
+		burst    int
+		requests int
+		// minWait là thời gian tối thiểu để gửi hết `requests` request
+		minWait time.Duration
+		maxWait time.Duration
+	}{
+		{name: "within burst", rate: 10, burst: 3, requests: 3, maxWait: 50 * time.Millisecond},
+		{name: "beyond burst", rate: 50, burst: 2, requests: 4, minWait: 35 * time.Millisecond, maxWait: 500 * time.Millisecond},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := newRateLimiter(test.rate, test.burst, 0)
+			start := time.Now()
+			for i := 0; i < test.requests; i++ {
+				release, err := l.acquire(context.Background())
+				if err != nil {
+					t.Fatalf("acquire: %v", err)
+				}
+				release()
+			}
+			elapsed := time.Since(start)
+			if elapsed < test.minWait || elapsed > test.maxWait {
+				t.Fatalf("%d requests took %s, want between %s and %s", test.requests, elapsed, test.minWait, test.maxWait)
+			}
+		})
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	l := newRateLimiter(1, 1, 0)
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = l.acquire(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("acquire error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRateLimiterMaxInFlight(t *testing.T) {
+	const maxInFlight = 3
+	l := newRateLimiter(0, 0, maxInFlight)
+
+	var (
+		wg       sync.WaitGroup
+		inFlight int32
+		peak     int32
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := l.acquire(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer release()
+
+			current := atomic.AddInt32(&inFlight, 1)
+			for {
+				old := atomic.LoadInt32(&peak)
+				if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+		}()
+	}
+	wg.Wait()
+
+	if peak > maxInFlight {
+		t.Fatalf("peak in flight = %d, want at most %d", peak, maxInFlight)
+	}
+}
+
+func TestRateLimiterReleaseOnce(t *testing.T) {
+	l := newRateLimiter(0, 0, 1)
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	// gọi release nhiều lần không được trả lại chỗ của request khác
+	release()
+
+	second, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = l.acquire(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("acquire error = %v, want context.DeadlineExceeded while the slot is taken", err)
+	}
+}
+
+func TestNewClientWebsocketIgnoresRateLimit(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	ws := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer ws.Close()
+
+	c, err := NewClient("ws://"+strings.TrimPrefix(ws.URL, "http://"), WithRateLimit(1, 1), WithMaxInFlight(1))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+	if c.Endpoints() != nil {
+		t.Fatalf("websocket client has endpoints %+v", c.Endpoints())
+	}
+}
@@ -0,0 +1,175 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// DefaultReceiptPollInterval là chu kỳ mặc định kiểm tra receipt của transaction, bằng thời gian ra block của bsc
+const DefaultReceiptPollInterval = 3 * time.Second
+
+// ErrTxDropped được trả về khi transaction không được mine và nonce của nó đã được dùng bởi transaction khác
+var ErrTxDropped = errors.New("transaction was dropped, its nonce was used by another transaction")
+
+// MinedResult là kết quả của transaction đã được mine
+type MinedResult struct {
+	Receipt *types.Receipt
+
+	// Reverted là true nếu transaction bị revert
+	Reverted bool
+
+	// Confirmations là số block tính từ block chứa transaction tới block mới nhất, bao gồm cả block chứa transaction
+	Confirmations uint64
+
+	// Events là các log chưa decode của transaction, decode bằng các hàm Parse*Events hoặc dùng WaitMinedEvents
+	Events []*FilterChange
+}
+
+// WaitMined đợi transaction `txHash` được mine và có ít nhất `confirmations` block xác nhận
+// receipt được lấy lại sau mỗi lần kiểm tra nên nếu block chứa transaction bị reorg
+// thì WaitMined tiếp tục đợi transaction được mine lại
+// nếu transaction bị drop khỏi mempool và nonce của nó đã được dùng bởi transaction khác, ví dụ transaction
+// thay thế bằng SpeedUpTransaction hoặc CancelTransaction, WaitMined trả về ErrTxDropped
+// WaitMined chỉ dừng khi có kết quả, khi có lỗi hoặc khi `ctx` bị huỷ
+func (c *Client) WaitMined(ctx context.Context, txHash common.Hash, confirmations uint64) (*MinedResult, error) {
+	ticker := time.NewTicker(c.receiptPollInterval)
+	defer ticker.Stop()
+
+	var (
+		from    common.Address
+		nonce   uint64
+		tracked bool
+	)
+	for {
+		if !tracked {
+			var err error
+			from, nonce, tracked, err = c.transactionNonce(ctx, txHash)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// lấy nonce đã mine trước khi lấy receipt để không nhầm transaction vừa được mine là transaction bị drop
+		var minedNonce uint64
+		if tracked {
+			var err error
+			minedNonce, err = c.backend.NonceAt(ctx, from, nil)
+			if err != nil {
+				return nil, errors.Wrap(err, "client get nonce error")
+			}
+		}
+
+		result, err := c.minedResult(ctx, txHash)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil && result.Confirmations >= confirmations {
+			return result, nil
+		}
+		if result == nil && tracked && minedNonce > nonce {
+			if c.cache != nil {
+				for _, hash := range c.ReplacementSet(txHash) {
+					c.cache.observeMined(hash)
+				}
+			}
+			return nil, ErrTxDropped
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "client wait mined error")
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitMinedEvents đợi như WaitMined rồi chuyển các log của transaction cho `handler`, ví dụ để decode bằng các hàm Parse*Events
+// `handler` không được gọi nếu transaction bị revert
+func (c *Client) WaitMinedEvents(ctx context.Context, txHash common.Hash, confirmations uint64, handler LogHandler) (*MinedResult, error) {
+	result, err := c.WaitMined(ctx, txHash, confirmations)
+	if err != nil {
+		return nil, err
+	}
+	if result.Reverted || len(result.Events) == 0 {
+		return result, nil
+	}
+	if err = handler(ctx, result.Events); err != nil {
+		return result, errors.Wrap(err, "client handle mined events error")
+	}
+	return result, nil
+}
+
+// transactionNonce trả về ví gửi và nonce của transaction `txHash`, lấy từ NonceManager nếu transaction được gửi
+// từ client, nếu không thì từ node. Trả về false nếu chưa biết, ví dụ node không còn giữ transaction
+func (c *Client) transactionNonce(ctx context.Context, txHash common.Hash) (common.Address, uint64, bool, error) {
+	if from, nonce, ok := c.nonces.sentTransaction(txHash); ok {
+		return from, nonce, true, nil
+	}
+
+	var tx *types.Transaction
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		tx, _, err = c.backend.TransactionByHash(ctx, txHash)
+		return err
+	})
+	if err == ethereum.NotFound {
+		return common.Address{}, 0, false, nil
+	}
+	if err != nil {
+		return common.Address{}, 0, false, errors.Wrap(err, "client get transaction error")
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return common.Address{}, 0, false, errors.Wrap(err, "client recover transaction sender error")
+	}
+	return from, tx.Nonce(), true, nil
+}
+
+// minedResult trả về nil nếu transaction chưa được mine
+func (c *Client) minedResult(ctx context.Context, txHash common.Hash) (*MinedResult, error) {
+	var receipt *types.Receipt
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		receipt, err = c.backend.TransactionReceipt(ctx, txHash)
+		return err
+	})
+	if err != nil && err != ethereum.NotFound {
+		return nil, errors.Wrap(err, "client get transaction receipt error")
+	}
+	// backends.SimulatedBackend trả về receipt nil không kèm lỗi khi transaction chưa được mine
+	if receipt == nil {
+		return nil, nil
+	}
+
+	var head uint64
+	err = c.retry(ctx, func(ctx context.Context) (err error) {
+		head, err = c.blockNumber(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "client get block number error")
+	}
+
+	result := &MinedResult{
+		Receipt:  receipt,
+		Reverted: receipt.Status == types.ReceiptStatusFailed,
+		Events:   make([]*FilterChange, len(receipt.Logs)),
+	}
+	if mined := receipt.BlockNumber.Uint64(); head >= mined {
+		result.Confirmations = head - mined + 1
+	}
+	for i, log := range receipt.Logs {
+		result.Events[i] = NewFilterChange(log)
+	}
+	if c.cache != nil {
+		c.cache.ObserveEvents(result.Events)
+		// các transaction cùng nonce không còn được mine nên không còn chờ
+		for _, hash := range c.ReplacementSet(txHash) {
+			c.cache.observeMined(hash)
+		}
+	}
+	return result, nil
+}
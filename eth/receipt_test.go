@@ -0,0 +1,144 @@
+package eth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	stderrors "errors"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestClientWaitMined(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	tests := []struct {
+		name          string
+		confirmations uint64
+		// after chạy sau khi client gửi transaction, trước khi WaitMined
+		after             func(t *testing.T, sim *backends.SimulatedBackend, key *ecdsa.PrivateKey)
+		wantConfirmations uint64
+		wantErr           error
+	}{
+		{
+			name:          "mined",
+			confirmations: 1,
+			after: func(t *testing.T, sim *backends.SimulatedBackend, key *ecdsa.PrivateKey) {
+				sim.Commit()
+			},
+			wantConfirmations: 1,
+		},
+		{
+			name:          "confirmed by later blocks",
+			confirmations: 3,
+			after: func(t *testing.T, sim *backends.SimulatedBackend, key *ecdsa.PrivateKey) {
+				for i := 0; i < 4; i++ {
+					sim.Commit()
+				}
+			},
+			wantConfirmations: 4,
+		},
+		{
+			name:          "not enough confirmations",
+			confirmations: 3,
+			after: func(t *testing.T, sim *backends.SimulatedBackend, key *ecdsa.PrivateKey) {
+				sim.Commit()
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:          "pending",
+			confirmations: 1,
+			after:         func(t *testing.T, sim *backends.SimulatedBackend, key *ecdsa.PrivateKey) {},
+			wantErr:       context.DeadlineExceeded,
+		},
+		{
+			name:          "replaced by another transaction",
+			confirmations: 1,
+			after: func(t *testing.T, sim *backends.SimulatedBackend, key *ecdsa.PrivateKey) {
+				// transaction bị drop khỏi mempool rồi nonce 0 được dùng bởi transaction gửi ngoài client
+				sim.Rollback()
+				signer := types.LatestSignerForChainID(params.AllEthashProtocolChanges.ChainID)
+				tx, err := types.SignNewTx(key, signer, &types.LegacyTx{
+					Nonce:    0,
+					To:       &to,
+					Value:    big.NewInt(2),
+					Gas:      params.TxGas,
+					GasPrice: big.NewInt(params.GWei),
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err = sim.SendTransaction(context.Background(), tx); err != nil {
+					t.Fatal(err)
+				}
+				sim.Commit()
+			},
+			wantErr: eth.ErrTxDropped,
+		},
+		{
+			name:          "dropped and not replaced",
+			confirmations: 1,
+			after: func(t *testing.T, sim *backends.SimulatedBackend, key *ecdsa.PrivateKey) {
+				sim.Rollback()
+				sim.Commit()
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key, err := crypto.GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			from := crypto.PubkeyToAddress(key.PublicKey)
+			sim := backends.NewSimulatedBackend(core.GenesisAlloc{from: {Balance: big.NewInt(params.Ether)}}, 8_000_000)
+			defer sim.Close()
+
+			gasPrice, err := eth.NewFixedGasPrice(big.NewInt(params.GWei))
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := eth.AsClient(sim,
+				eth.WithPrivateKey(key),
+				eth.WithChainID(params.AllEthashProtocolChanges.ChainID),
+				eth.WithGasStrategy(gasPrice),
+				eth.WithReceiptPollInterval(10*time.Millisecond),
+			)
+
+			txHash, err := client.SendTransaction(context.Background(), to, big.NewInt(1), nil)
+			if err != nil {
+				t.Fatalf("SendTransaction: %v", err)
+			}
+			test.after(t, sim, key)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			result, err := client.WaitMined(ctx, txHash, test.confirmations)
+			if test.wantErr != nil {
+				if !stderrors.Is(err, test.wantErr) {
+					t.Fatalf("WaitMined error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WaitMined: %v", err)
+			}
+			if result.Receipt.TxHash != txHash || result.Reverted {
+				t.Fatalf("WaitMined = receipt of %s reverted %v, want %s", result.Receipt.TxHash.Hex(), result.Reverted, txHash.Hex())
+			}
+			if result.Confirmations != test.wantConfirmations {
+				t.Fatalf("Confirmations = %d, want %d", result.Confirmations, test.wantConfirmations)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/pkg/errors"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// MinGasBumpPercent là mức tăng giá gas tối thiểu (%) mà node yêu cầu để thay thế transaction cùng nonce
+const MinGasBumpPercent = 10
+
+// ErrTxNotPending được trả về khi thay thế transaction đã được mine hoặc không có trong mempool
+var ErrTxNotPending = errors.New("transaction is not pending")
+
+// ErrNonceUsedByOtherTx được trả về khi nonce của bộ transaction thay thế đã được dùng bởi transaction khác
+var ErrNonceUsedByOtherTx = errors.New("nonce was used by a transaction outside the replacement set")
+
+// replacementSet là các transaction có cùng nonce, chỉ một trong số đó được mine
+type replacementSet struct {
+	from     common.Address
+	nonce    uint64
+	txHashes []common.Hash
+}
+
+type replacements struct {
+	mu   sync.Mutex
+	sets map[common.Hash]*replacementSet
+}
+
+func (r *replacements) add(original *types.Transaction, from common.Address, replacement common.Hash) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.sets == nil {
+		r.sets = map[common.Hash]*replacementSet{}
+	}
+	set, ok := r.sets[original.Hash()]
+	if !ok {
+		set = &replacementSet{
+			from:     from,
+			nonce:    original.Nonce(),
+			txHashes: []common.Hash{original.Hash()},
+		}
+		r.sets[original.Hash()] = set
+	}
+	set.txHashes = append(set.txHashes, replacement)
+	r.sets[replacement] = set
+}
+
+func (r *replacements) get(txHash common.Hash) (from common.Address, nonce uint64, txHashes []common.Hash, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	set, ok := r.sets[txHash]
+	if !ok {
+		return common.Address{}, 0, nil, false
+	}
+	return set.from, set.nonce, append([]common.Hash(nil), set.txHashes...), true
+}
+
+// ReplacementSet trả về hash của transaction gốc và tất cả transaction đã thay thế nó, theo thứ tự gửi
+// `txHash` là hash của bất kỳ transaction nào trong bộ
+func (c *Client) ReplacementSet(txHash common.Hash) []common.Hash {
+	_, _, txHashes, ok := c.replacements.get(txHash)
+	if !ok {
+		return []common.Hash{txHash}
+	}
+	return txHashes
+}
+
+// SpeedUpTransaction gửi lại transaction `txHash` với cùng nonce và giá gas tăng ít nhất `bumpPercent`%
+// `bumpPercent` nhỏ hơn MinGasBumpPercent thì dùng MinGasBumpPercent
+// nếu giá gas hiện tại của GasStrategy cao hơn thì dùng giá gas hiện tại
+func (c *Client) SpeedUpTransaction(ctx context.Context, txHash common.Hash, bumpPercent int) (common.Hash, error) {
+	original, from, err := c.pendingOwnTransaction(ctx, txHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return c.replaceTransaction(ctx, original, from, *original.To(), original.Value(), original.Gas(), original.Data(), bumpPercent)
+}
+
+// CancelTransaction huỷ transaction `txHash` bằng cách gửi transaction chuyển 0 wei cho chính ví gửi
+// với cùng nonce và giá gas tăng ít nhất `bumpPercent`%
+func (c *Client) CancelTransaction(ctx context.Context, txHash common.Hash, bumpPercent int) (common.Hash, error) {
+	original, from, err := c.pendingOwnTransaction(ctx, txHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return c.replaceTransaction(ctx, original, from, from, new(big.Int), params.TxGas, nil, bumpPercent)
+}
+
+// WaitReplacementMined đợi một transaction trong bộ thay thế của `txHash` được mine
+// với ít nhất `confirmations` block xác nhận, Receipt.TxHash của kết quả cho biết transaction nào được mine
+func (c *Client) WaitReplacementMined(ctx context.Context, txHash common.Hash, confirmations uint64) (*MinedResult, error) {
+	ticker := time.NewTicker(c.receiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		from, nonce, txHashes, tracked := c.replacements.get(txHash)
+		if !tracked {
+			txHashes = []common.Hash{txHash}
+		}
+
+		// lấy nonce đã mine trước khi lấy receipt để không nhầm transaction vừa được mine là transaction khác
+		var minedNonce uint64
+		if tracked {
+			var err error
+			minedNonce, err = c.backend.NonceAt(ctx, from, nil)
+			if err != nil {
+				return nil, errors.Wrap(err, "client get nonce error")
+			}
+		}
+
+		found := false
+		for _, hash := range txHashes {
+			result, err := c.minedResult(ctx, hash)
+			if err != nil {
+				return nil, err
+			}
+			if result == nil {
+				continue
+			}
+			if result.Confirmations >= confirmations {
+				return result, nil
+			}
+			found = true
+		}
+		if tracked && !found && minedNonce > nonce {
+			return nil, ErrNonceUsedByOtherTx
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "client wait replacement mined error")
+		case <-ticker.C:
+		}
+	}
+}
+
+// pendingOwnTransaction lấy transaction `txHash` đang chờ trong mempool và kiểm tra nó được gửi từ ví của client
+func (c *Client) pendingOwnTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Address, error) {
+	from, err := c.From()
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	tx, isPending, err := c.backend.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, common.Address{}, errors.Wrap(err, "client get transaction error")
+	}
+	if !isPending {
+		return nil, common.Address{}, ErrTxNotPending
+	}
+	if tx.To() == nil {
+		return nil, common.Address{}, errors.New("client can not replace contract creation transaction")
+	}
+
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return nil, common.Address{}, errors.Wrap(err, "client recover transaction sender error")
+	}
+	if sender != from {
+		return nil, common.Address{}, errors.Errorf("transaction was sent from %s, not from client %s", sender.Hex(), from.Hex())
+	}
+	return tx, from, nil
+}
+
+func (c *Client) replaceTransaction(ctx context.Context, original *types.Transaction, from common.Address, to common.Address, value *big.Int, gas uint64, data []byte, bumpPercent int) (common.Hash, error) {
+	if bumpPercent < MinGasBumpPercent {
+		bumpPercent = MinGasBumpPercent
+	}
+
+	current, err := c.gasStrategy.GasFees(ctx, c)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "client gas strategy error")
+	}
+	fees := bumpGasFees(original, current, bumpPercent)
+	if err = c.checkGasCeiling(fees); err != nil {
+		return common.Hash{}, err
+	}
+
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	// transaction gốc vẫn giữ nonce nên không trả lại nonce cho NonceManager khi gửi lỗi
+	tx := newTx(chainID, original.Nonce(), to, value, gas, fees, data)
+	signedTx, err := c.signAndSendTx(ctx, tx)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "client replace transaction error")
+	}
+
+	c.nonces.Sent(from, original.Nonce(), signedTx.Hash())
+	c.replacements.add(original, from, signedTx.Hash())
+	if c.cache != nil {
+		c.cache.observeReplacement(original.Hash(), signedTx.Hash())
+		c.cache.observeTransaction(signedTx.Hash(), to, data)
+	}
+	return signedTx.Hash(), nil
+}
+
+// bumpGasFees tăng giá gas của `original` thêm `bumpPercent`%, nếu giá `current` cao hơn thì dùng `current`
+// transaction thay thế giữ cùng loại với transaction gốc
+func bumpGasFees(original *types.Transaction, current *GasFees, bumpPercent int) *GasFees {
+	if original.Type() == types.LegacyTxType {
+		gasPrice := bumpPercentage(original.GasPrice(), bumpPercent)
+		if current.IsLegacy() && current.GasPrice.Cmp(gasPrice) > 0 {
+			gasPrice = current.GasPrice
+		}
+		return &GasFees{GasPrice: gasPrice}
+	}
+
+	tip := bumpPercentage(original.GasTipCap(), bumpPercent)
+	feeCap := bumpPercentage(original.GasFeeCap(), bumpPercent)
+	if !current.IsLegacy() {
+		if current.GasTipCap.Cmp(tip) > 0 {
+			tip = current.GasTipCap
+		}
+		if current.GasFeeCap.Cmp(feeCap) > 0 {
+			feeCap = current.GasFeeCap
+		}
+	}
+	if tip.Cmp(feeCap) > 0 {
+		feeCap = new(big.Int).Set(tip)
+	}
+	return &GasFees{GasTipCap: tip, GasFeeCap: feeCap}
+}
+
+// bumpPercentage trả về value * (100 + percent) / 100, làm tròn lên để chắc chắn đủ mức tăng
+func bumpPercentage(value *big.Int, percent int) *big.Int {
+	result := new(big.Int).Mul(value, big.NewInt(int64(100+percent)))
+	result.Add(result, big.NewInt(99))
+	return result.Div(result, big.NewInt(100))
+}
@@ -0,0 +1,58 @@
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"testing"
+)
+
+func TestBumpGasFees(t *testing.T) {
+	legacy := func(gasPrice int64) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(gasPrice)})
+	}
+	dynamic := func(tip, feeCap int64) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(tip), GasFeeCap: big.NewInt(feeCap)})
+	}
+	legacyFees := func(gasPrice int64) *GasFees {
+		return &GasFees{GasPrice: big.NewInt(gasPrice)}
+	}
+	dynamicFees := func(tip, feeCap int64) *GasFees {
+		return &GasFees{GasTipCap: big.NewInt(tip), GasFeeCap: big.NewInt(feeCap)}
+	}
+
+	tests := []struct {
+		name     string
+		original *types.Transaction
+		current  *GasFees
+		percent  int
+		want     *GasFees
+	}{
+		{name: "legacy bumped", original: legacy(100), current: legacyFees(90), percent: 10, want: legacyFees(110)},
+		{name: "legacy rounds up", original: legacy(3), current: legacyFees(1), percent: 10, want: legacyFees(4)},
+		{name: "legacy current price is higher", original: legacy(100), current: legacyFees(150), percent: 10, want: legacyFees(150)},
+		{name: "legacy keeps type", original: legacy(100), current: dynamicFees(500, 1000), percent: 10, want: legacyFees(110)},
+		{name: "dynamic bumped", original: dynamic(10, 100), current: dynamicFees(5, 50), percent: 10, want: dynamicFees(11, 110)},
+		{name: "dynamic current tip is higher", original: dynamic(10, 100), current: dynamicFees(20, 50), percent: 10, want: dynamicFees(20, 110)},
+		{name: "dynamic current fee cap is higher", original: dynamic(10, 100), current: dynamicFees(5, 200), percent: 10, want: dynamicFees(11, 200)},
+		{name: "dynamic keeps type", original: dynamic(10, 100), current: legacyFees(500), percent: 10, want: dynamicFees(11, 110)},
+		{name: "fee cap not below tip", original: dynamic(10, 100), current: dynamicFees(300, 50), percent: 10, want: dynamicFees(300, 300)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := bumpGasFees(test.original, test.current, test.percent)
+			if got.IsLegacy() != test.want.IsLegacy() {
+				t.Fatalf("bumpGasFees legacy = %v, want %v", got.IsLegacy(), test.want.IsLegacy())
+			}
+			if got.IsLegacy() {
+				if got.GasPrice.Cmp(test.want.GasPrice) != 0 {
+					t.Fatalf("gas price = %s, want %s", got.GasPrice, test.want.GasPrice)
+				}
+				return
+			}
+			if got.GasTipCap.Cmp(test.want.GasTipCap) != 0 || got.GasFeeCap.Cmp(test.want.GasFeeCap) != 0 {
+				t.Fatalf("fees = tip %s, fee cap %s, want tip %s, fee cap %s", got.GasTipCap, got.GasFeeCap, test.want.GasTipCap, test.want.GasFeeCap)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package eth
+
+import (
+	"context"
+	stderrors "errors"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RetryPolicy là chính sách gọi lại các request RPC đọc dữ liệu khi gặp lỗi tạm thời
+// các transaction gửi lên node không được gọi lại theo chính sách này
+type RetryPolicy struct {
+	// MaxAttempts là số lần gọi tối đa, bao gồm lần đầu tiên, nhỏ hơn 2 là không gọi lại
+	MaxAttempts int
+
+	// InitialBackoff là thời gian đợi trước lần gọi lại đầu tiên
+	InitialBackoff time.Duration
+
+	// MaxBackoff là thời gian đợi tối đa giữa hai lần gọi
+	MaxBackoff time.Duration
+
+	// Multiplier là hệ số tăng thời gian đợi sau mỗi lần gọi lại
+	Multiplier float64
+
+	// Jitter là tỉ lệ ngẫu nhiên (0..1) cộng trừ vào thời gian đợi để các client không gọi lại cùng lúc
+	Jitter float64
+
+	// AttemptTimeout là thời gian tối đa của một lần gọi, 0 là chỉ dùng deadline của context
+	AttemptTimeout time.Duration
+
+	// Retryable quyết định lỗi có được gọi lại hay không, nil là dùng IsRetryableError
+	Retryable func(err error) bool
+}
+
+// NoRetry là chính sách không gọi lại, là mặc định của Client
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// DefaultRetryPolicy là chính sách gọi lại phù hợp với các public node của bsc
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+	AttemptTimeout: 10 * time.Second,
+}
+
+// Do gọi `fn` cho tới khi thành công, gặp lỗi không gọi lại được, hết số lần gọi hoặc `ctx` bị huỷ
+// mỗi lần gọi nhận một context con có timeout AttemptTimeout
+func (p RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryableError
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = p.attempt(ctx, fn)
+		if err == nil {
+			return nil
+		}
+
+		// context của người gọi đã bị huỷ hoặc hết hạn thì không gọi lại
+		if ctx.Err() != nil || attempt >= p.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func (p RetryPolicy) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p.AttemptTimeout <= 0 {
+		return fn(ctx)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, p.AttemptTimeout)
+	defer cancel()
+	return fn(attemptCtx)
+}
+
+// backoff trả về thời gian đợi sau lần gọi thứ `attempt`
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		backoff += backoff * p.Jitter * (rand.Float64()*2 - 1)
+	}
+	return time.Duration(backoff)
+}
+
+// retryableMessages là các lỗi tạm thời node trả về trong JSON-RPC error
+var retryableMessages = []string{
+	"header not found",
+	"rate limit",
+	"too many requests",
+	"limit exceeded",
+	"request timed out",
+	"timeout",
+	"server busy",
+	"connection reset",
+	"connection refused",
+	"unexpected eof",
+}
+
+// IsRetryableError phân loại lỗi tạm thời có thể gọi lại: HTTP 408/429/5xx, lỗi mạng, timeout
+// của một lần gọi và các lỗi node như "header not found" hay vượt giới hạn request
+// lỗi revert của contract và các lỗi khác của node là lỗi cố định
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var revertErr *RevertError
+	if stderrors.As(err, &revertErr) {
+		return false
+	}
+
+	// lỗi vượt giới hạn của `eth_getLogs` (infura dùng mã -32005) chỉ hết khi thu nhỏ khoảng block
+	if IsLogRangeError(err) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if stderrors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusRequestTimeout ||
+			httpErr.StatusCode == http.StatusTooManyRequests ||
+			httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	cause := errors.Cause(err)
+	if cause == context.DeadlineExceeded || cause == io.EOF || cause == io.ErrUnexpectedEOF {
+		return true
+	}
+
+	var netErr net.Error
+	if stderrors.As(err, &netErr) {
+		return true
+	}
+
+	if rpcErr, ok := cause.(rpc.Error); ok && rpcErr.ErrorCode() == -32005 {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, retryableMessage := range retryableMessages {
+		if strings.Contains(msg, retryableMessage) {
+			return true
+		}
+	}
+	return false
+}
+
+// retry gọi `fn` theo RetryPolicy của client
+func (c *Client) retry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return c.retryPolicy.Do(ctx, fn)
+}
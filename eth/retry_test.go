@@ -0,0 +1,150 @@
+package eth
+
+import (
+	"context"
+	stderrors "errors"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"http 408", rpc.HTTPError{StatusCode: 408}, true},
+		{"http 429", rpc.HTTPError{StatusCode: 429}, true},
+		{"http 502", errors.Wrap(rpc.HTTPError{StatusCode: 502}, "call rpc error"), true},
+		{"http 400", rpc.HTTPError{StatusCode: 400}, false},
+		{"http 404", rpc.HTTPError{StatusCode: 404}, false},
+		{"attempt timeout", errors.Wrap(context.DeadlineExceeded, "call rpc error"), true},
+		{"caller cancelled", context.Canceled, false},
+		{"eof", io.ErrUnexpectedEOF, true},
+		{"network error", &net.OpError{Op: "dial", Err: stderrors.New("connection refused")}, true},
+		{"limit exceeded code", &RPCError{Code: -32005, Message: "daily request count exceeded"}, true},
+		{"header not found", &RPCError{Code: -32000, Message: "header not found"}, true},
+		{"rate limit message", stderrors.New("429: Rate limit reached"), true},
+		{"log range", &RPCError{Code: -32005, Message: "query returned more than 10000 results"}, false},
+		{"revert", errors.Wrap(&RevertError{Reason: "timeout"}, "call contract error"), false},
+		{"nonce too low", &RPCError{Code: -32000, Message: "nonce too low"}, false},
+		{"invalid params", &RPCError{Code: -32602, Message: "invalid argument 0"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := IsRetryableError(test.err); got != test.want {
+				t.Fatalf("IsRetryableError(%v) = %v, want %v", test.err, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		want    time.Duration
+	}{
+		{"first", RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2}, 1, 100 * time.Millisecond},
+		{"exponential", RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2}, 4, 800 * time.Millisecond},
+		{"capped", RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2, MaxBackoff: 300 * time.Millisecond}, 4, 300 * time.Millisecond},
+		{"multiplier below one is constant", RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 0.5}, 3, 100 * time.Millisecond},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.policy.backoff(test.attempt); got != test.want {
+				t.Fatalf("backoff(%d) = %s, want %s", test.attempt, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoffJitter(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2, Jitter: 0.2}
+	for i := 0; i < 100; i++ {
+		got := policy.backoff(2)
+		if got < 160*time.Millisecond || got > 240*time.Millisecond {
+			t.Fatalf("backoff(2) = %s, want 200ms ± 20%%", got)
+		}
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	transient := &RPCError{Code: -32000, Message: "header not found"}
+	permanent := &RPCError{Code: -32602, Message: "invalid argument 0"}
+
+	tests := []struct {
+		name string
+		// errs là lỗi của từng lần gọi, hết danh sách thì thành công
+		errs         []error
+		maxAttempts  int
+		wantAttempts int
+		wantErr      error
+	}{
+		{name: "success", maxAttempts: 3, wantAttempts: 1},
+		{name: "transient then success", errs: []error{transient, transient}, maxAttempts: 3, wantAttempts: 3},
+		{name: "attempts exhausted", errs: []error{transient, transient, transient}, maxAttempts: 3, wantAttempts: 3, wantErr: transient},
+		{name: "permanent", errs: []error{permanent}, maxAttempts: 3, wantAttempts: 1, wantErr: permanent},
+		{name: "no retry", errs: []error{transient}, maxAttempts: 1, wantAttempts: 1, wantErr: transient},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := RetryPolicy{MaxAttempts: test.maxAttempts, InitialBackoff: time.Millisecond}
+			attempts := 0
+			err := policy.Do(context.Background(), func(ctx context.Context) error {
+				attempts++
+				if attempts <= len(test.errs) {
+					return test.errs[attempts-1]
+				}
+				return nil
+			})
+			if attempts != test.wantAttempts {
+				t.Fatalf("attempts = %d, want %d", attempts, test.wantAttempts)
+			}
+			if err != test.wantErr {
+				t.Fatalf("Do error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDoStopsWhenCancelled(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	attempts := 0
+	err := policy.Do(ctx, func(ctx context.Context) error {
+		attempts++
+		return rpc.HTTPError{StatusCode: 503}
+	})
+	if attempts != 1 || err == nil {
+		t.Fatalf("Do = %d attempts, %v; want 1 attempt and an error", attempts, err)
+	}
+}
+
+func TestRetryPolicyAttemptTimeout(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, AttemptTimeout: 10 * time.Millisecond}
+
+	attempts := 0
+	err := policy.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("Do = %d attempts, %v; want the timed out attempt retried", attempts, err)
+	}
+}
@@ -0,0 +1,132 @@
+package eth
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+)
+
+var (
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+// panicReasons là ý nghĩa của các mã lỗi Panic(uint256) do solidity sinh ra
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler inserted panic",
+	0x01: "assert failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid encoded storage byte array",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to uninitialized function",
+}
+
+// RevertError là lỗi khi contract bị revert, dùng errors.As để lấy ra từ lỗi của client
+type RevertError struct {
+	// Data là dữ liệu revert node trả về, có thể rỗng nếu node không trả về
+	Data []byte
+
+	// Reason là thông điệp của Error(string), ý nghĩa của mã panic hoặc chữ ký của custom error
+	Reason string
+
+	// PanicCode là mã lỗi của Panic(uint256), nil nếu không phải panic
+	PanicCode *big.Int
+
+	// CustomError là custom error trong abi của contract, nil nếu không phải custom error
+	CustomError *abi.Error
+
+	// Args là các tham số đã decode của custom error
+	Args []interface{}
+}
+
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return "execution reverted"
+	}
+	return "execution reverted: " + e.Reason
+}
+
+// IsPanic trả về true nếu contract revert bằng Panic(uint256)
+func (e *RevertError) IsPanic() bool {
+	return e.PanicCode != nil
+}
+
+// IsCustomError trả về true nếu contract revert bằng custom error có trong abi
+func (e *RevertError) IsCustomError() bool {
+	return e.CustomError != nil
+}
+
+// DecodeRevert decode dữ liệu revert `data` theo Error(string), Panic(uint256)
+// hoặc các custom error trong `contractABI`
+func DecodeRevert(contractABI *abi.ABI, data []byte) *RevertError {
+	revertErr := &RevertError{Data: data}
+	if len(data) < 4 {
+		return revertErr
+	}
+
+	selector := data[:4]
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			revertErr.Reason = reason
+		}
+	case bytes.Equal(selector, panicSelector):
+		if len(data) >= 36 {
+			code := new(big.Int).SetBytes(data[4:36])
+			revertErr.PanicCode = code
+			reason, ok := panicReasons[code.Uint64()]
+			if !ok || !code.IsUint64() {
+				reason = "unknown panic"
+			}
+			revertErr.Reason = fmt.Sprintf("panic 0x%x: %s", code, reason)
+		}
+	case contractABI != nil:
+		for _, customError := range contractABI.Errors {
+			if !bytes.Equal(selector, customError.ID[:4]) {
+				continue
+			}
+			customError := customError
+			revertErr.CustomError = &customError
+			revertErr.Reason = customError.Sig
+			if args, err := customError.Inputs.Unpack(data[4:]); err == nil {
+				revertErr.Args = args
+			}
+			break
+		}
+	}
+	return revertErr
+}
+
+// revertError chuyển lỗi của node khi gọi hoặc estimate gas thành RevertError nếu contract bị revert
+// các lỗi khác được trả về nguyên vẹn
+func revertError(contractABI *abi.ABI, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if dataErr, ok := errors.Cause(err).(rpc.DataError); ok {
+		if hexData, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(hexData); decodeErr == nil {
+				return DecodeRevert(contractABI, data)
+			}
+		}
+	}
+
+	// node không trả về dữ liệu revert, chỉ có thông điệp
+	const prefix = "execution reverted"
+	msg := errors.Cause(err).Error()
+	if strings.HasPrefix(msg, prefix) {
+		return &RevertError{Reason: strings.TrimPrefix(strings.TrimPrefix(msg, prefix), ": ")}
+	}
+	return err
+}
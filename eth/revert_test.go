@@ -0,0 +1,134 @@
+package eth_test
+
+import (
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+const vaultABIJSON = `[
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]},
+	{"type":"error","name":"Unauthorized","inputs":[]}
+]`
+
+var vaultABI = mustParseABI(vaultABIJSON)
+
+func TestDecodeRevert(t *testing.T) {
+	encode := func(signature string, types []string, args ...interface{}) []byte {
+		arguments := make(abi.Arguments, len(types))
+		for i, name := range types {
+			typ, err := abi.NewType(name, "", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			arguments[i] = abi.Argument{Type: typ}
+		}
+		packed, err := arguments.Pack(args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append(crypto.Keccak256([]byte(signature))[:4], packed...)
+	}
+	overflow := new(big.Int).Lsh(big.NewInt(1), 70)
+
+	tests := []struct {
+		name       string
+		abi        *abi.ABI
+		data       []byte
+		wantReason string
+		wantPanic  *big.Int
+		wantCustom string
+		wantArgs   []interface{}
+	}{
+		{name: "no data"},
+		{name: "short data", data: []byte{1, 2}},
+		{name: "error string", data: encode("Error(string)", []string{"string"}, "not owner"), wantReason: "not owner"},
+		{
+			name:       "assert",
+			data:       encode("Panic(uint256)", []string{"uint256"}, big.NewInt(0x01)),
+			wantReason: "panic 0x1: assert failed",
+			wantPanic:  big.NewInt(0x01),
+		},
+		{
+			name:       "overflow",
+			data:       encode("Panic(uint256)", []string{"uint256"}, big.NewInt(0x11)),
+			wantReason: "panic 0x11: arithmetic underflow or overflow",
+			wantPanic:  big.NewInt(0x11),
+		},
+		{
+			name:       "unknown panic code",
+			data:       encode("Panic(uint256)", []string{"uint256"}, big.NewInt(0x99)),
+			wantReason: "panic 0x99: unknown panic",
+			wantPanic:  big.NewInt(0x99),
+		},
+		{
+			name:       "panic code above uint64",
+			data:       encode("Panic(uint256)", []string{"uint256"}, overflow),
+			wantReason: "panic 0x400000000000000000: unknown panic",
+			wantPanic:  overflow,
+		},
+		{name: "truncated panic", data: crypto.Keccak256([]byte("Panic(uint256)"))[:4]},
+		{
+			name:       "custom error",
+			abi:        &vaultABI,
+			data:       encode("InsufficientBalance(uint256,uint256)", []string{"uint256", "uint256"}, big.NewInt(5), big.NewInt(8)),
+			wantReason: "InsufficientBalance(uint256,uint256)",
+			wantCustom: "InsufficientBalance",
+			wantArgs:   []interface{}{big.NewInt(5), big.NewInt(8)},
+		},
+		{
+			name:       "custom error without arguments",
+			abi:        &vaultABI,
+			data:       encode("Unauthorized()", nil),
+			wantReason: "Unauthorized()",
+			wantCustom: "Unauthorized",
+			wantArgs:   []interface{}{},
+		},
+		{
+			name: "custom error not in abi",
+			abi:  &vaultABI,
+			data: encode("Paused()", nil),
+		},
+		{
+			name: "custom error without abi",
+			data: encode("Unauthorized()", nil),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			revertErr := eth.DecodeRevert(test.abi, test.data)
+			if revertErr.Reason != test.wantReason {
+				t.Fatalf("Reason = %q, want %q", revertErr.Reason, test.wantReason)
+			}
+			if revertErr.IsPanic() != (test.wantPanic != nil) {
+				t.Fatalf("IsPanic = %v, want %v", revertErr.IsPanic(), test.wantPanic != nil)
+			}
+			if test.wantPanic != nil && revertErr.PanicCode.Cmp(test.wantPanic) != 0 {
+				t.Fatalf("PanicCode = %s, want %s", revertErr.PanicCode, test.wantPanic)
+			}
+			if revertErr.IsCustomError() != (test.wantCustom != "") {
+				t.Fatalf("IsCustomError = %v, want %v", revertErr.IsCustomError(), test.wantCustom != "")
+			}
+			if test.wantCustom != "" {
+				if revertErr.CustomError.Name != test.wantCustom {
+					t.Fatalf("CustomError = %s, want %s", revertErr.CustomError.Name, test.wantCustom)
+				}
+				if !reflect.DeepEqual(revertErr.Args, test.wantArgs) {
+					t.Fatalf("Args = %v, want %v", revertErr.Args, test.wantArgs)
+				}
+			}
+
+			wantMessage := "execution reverted"
+			if test.wantReason != "" {
+				wantMessage += ": " + test.wantReason
+			}
+			if revertErr.Error() != wantMessage {
+				t.Fatalf("Error() = %q, want %q", revertErr.Error(), wantMessage)
+			}
+		})
+	}
+}
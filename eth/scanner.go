@@ -0,0 +1,131 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// DefaultScanChunkSize là số block mặc định của mỗi lần gọi `eth_getLogs`, bằng giới hạn của các node bsc công khai
+const DefaultScanChunkSize = 5000
+
+// logRangeMessages là các lỗi node trả về khi khoảng block hoặc số log của `eth_getLogs` vượt giới hạn
+var logRangeMessages = []string{
+	"query returned more than",
+	"block range too large",
+	"block range is too large",
+	"block range is too wide",
+	"exceed maximum block range",
+	"exceeds maximum block range",
+	"response size exceeded",
+	"too many blocks",
+}
+
+// LogHandler xử lý các log theo thứ tự block và vị trí trong block, ví dụ bằng các hàm Parse*Events
+type LogHandler func(ctx context.Context, changes []*FilterChange) error
+
+// IsLogRangeError trả về true nếu `err` là lỗi `eth_getLogs` vượt giới hạn khoảng block hoặc số log của node
+func IsLogRangeError(err error) bool {
+	if err == nil {
+		return false
+	}
+	rpcErr, ok := errors.Cause(err).(rpc.Error)
+	if !ok {
+		return false
+	}
+	msg := strings.ToLower(rpcErr.Error())
+	for _, m := range logRangeMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scanner đọc log trong một khoảng block bằng `eth_getLogs`, chia khoảng block thành các đoạn
+// khi node báo vượt giới hạn, đoạn bị chia đôi và đọc lại, sau mỗi lần đọc thành công đoạn được tăng gấp đôi
+// tới kích thước ban đầu. Scanner không dùng được đồng thời từ nhiều goroutine
+type Scanner struct {
+	client    *Client
+	query     FilterQuery
+	chunkSize uint64
+
+	chunk uint64
+	last  uint64
+}
+
+// NewScanner tạo Scanner đọc log khớp Addresses và Topics của `query`, mỗi lần đọc tối đa `chunkSize` block
+// `chunkSize` bằng 0 là DefaultScanChunkSize
+func (c *Client) NewScanner(query FilterQuery, chunkSize uint64) *Scanner {
+	if chunkSize == 0 {
+		chunkSize = DefaultScanChunkSize
+	}
+	return &Scanner{
+		client:    c,
+		query:     query,
+		chunkSize: chunkSize,
+		chunk:     chunkSize,
+	}
+}
+
+// ChunkSize trả về số block của lần đọc tiếp theo
+func (s *Scanner) ChunkSize() uint64 {
+	return s.chunk
+}
+
+// LastBlock trả về block cuối cùng đã đọc xong, dùng để lưu tiến độ trong `handler` của Scan
+func (s *Scanner) LastBlock() uint64 {
+	return s.last
+}
+
+// Scan đọc các log từ block `from` tới block `to`, bao gồm cả hai block, và chuyển log của từng đoạn cho `handler`
+// theo thứ tự block. `handler` chỉ được gọi với đoạn có log
+func (s *Scanner) Scan(ctx context.Context, from uint64, to uint64, handler LogHandler) error {
+	return s.scan(ctx, from, to, func(ctx context.Context, changes []*FilterChange) error {
+		s.client.observeLogs(changes)
+		return handler(ctx, changes)
+	})
+}
+
+// scan giống Scan nhưng không báo log cho LogObserver, dùng khi Watcher hoặc Stream tự báo các log đã chuyển đi
+func (s *Scanner) scan(ctx context.Context, from uint64, to uint64, handler LogHandler) error {
+	for from <= to {
+		end := from + s.chunk - 1
+		if end > to || end < from {
+			end = to
+		}
+
+		query := s.query
+		query.BlockHash = nil
+		query.FromBlock = AtBlock(from)
+		query.ToBlock = AtBlock(end)
+		changes, err := s.client.EthGetLogs(ctx, query)
+		if err != nil {
+			if size := end - from + 1; size > 1 && IsLogRangeError(err) {
+				s.chunk = size / 2
+				continue
+			}
+			return errors.Wrapf(err, "scanner get logs from block %d to %d error", from, end)
+		}
+
+		s.last = end
+		if len(changes) > 0 {
+			if err = handler(ctx, changes); err != nil {
+				return errors.Wrap(err, "scanner handle logs error")
+			}
+		}
+
+		if s.chunk < s.chunkSize {
+			s.chunk *= 2
+			if s.chunk > s.chunkSize {
+				s.chunk = s.chunkSize
+			}
+		}
+		if end == to {
+			break
+		}
+		from = end + 1
+	}
+	return nil
+}
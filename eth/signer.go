@@ -0,0 +1,182 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+)
+
+// Signer ký transaction cho Client, Client không cần biết khoá được lưu ở đâu
+type Signer interface {
+	// Address trả về địa chỉ ví của signer
+	Address() common.Address
+
+	// SignTx ký transaction `tx` cho mạng có chain id là `chainID`
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// NewPrivateKeySigner tạo Signer từ private key trong bộ nhớ
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) Signer {
+	return &privateKeySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+// NewHexKeySigner tạo Signer từ private key dạng hex
+func NewHexKeySigner(hexKey string) (Signer, error) {
+	key, err := crypto.HexToECDSA(hexKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid hex private key")
+	}
+	return NewPrivateKeySigner(key), nil
+}
+
+// NewKeystoreSigner tạo Signer từ file keystore JSON đã mã hoá của go-ethereum
+// `passphrase` dùng để giải mã file, khoá sau khi giải mã chỉ được giữ trong bộ nhớ
+func NewKeystoreSigner(keyFile string, passphrase string) (Signer, error) {
+	keyJSON, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "read keystore file error")
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypt keystore file error")
+	}
+	return NewPrivateKeySigner(key.PrivateKey), nil
+}
+
+type privateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+func (s *privateKeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *privateKeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "private key sign transaction error")
+	}
+	return signedTx, nil
+}
+
+// NewExternalSigner tạo Signer gọi tới signer bên ngoài (clef) qua JSON-RPC tại `endpoint`
+// transaction được ký bằng `account_signTransaction` với tài khoản `address`
+func NewExternalSigner(endpoint string, address common.Address) (Signer, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "dial external signer error")
+	}
+	return &externalSigner{
+		client:  client,
+		address: address,
+	}, nil
+}
+
+type externalSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+func (s *externalSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *externalSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := &apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(s.address),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	default:
+		return nil, errors.Errorf("external signer unsupported transaction type %d", tx.Type())
+	}
+
+	var result struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := s.client.CallContext(ctx, &result, "account_signTransaction", args); err != nil {
+		return nil, errors.Wrap(err, "external signer sign transaction error")
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(result.Raw); err != nil {
+		return nil, errors.Wrap(err, "external signer decode signed transaction error")
+	}
+
+	// signer bên ngoài có thể ký bằng tài khoản khác hoặc sửa transaction, kiểm tra lại trước khi gửi
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	if err != nil {
+		return nil, errors.Wrap(err, "external signer recover sender error")
+	}
+	if sender != s.address {
+		return nil, errors.Errorf("external signer signed with %s, expected %s", sender.Hex(), s.address.Hex())
+	}
+	if field := modifiedField(tx, signedTx, chainID); field != "" {
+		return nil, errors.Errorf("external signer modified the transaction %s", field)
+	}
+	return signedTx, nil
+}
+
+// modifiedField trả về tên trường đầu tiên của `signedTx` khác với `tx`, rỗng nếu hai transaction giống nhau
+func modifiedField(tx *types.Transaction, signedTx *types.Transaction, chainID *big.Int) string {
+	switch {
+	case signedTx.Type() != tx.Type():
+		return "type"
+	case signedTx.ChainId().Cmp(chainID) != 0:
+		return "chain id"
+	case signedTx.Nonce() != tx.Nonce():
+		return "nonce"
+	case (signedTx.To() == nil) != (tx.To() == nil) || tx.To() != nil && *signedTx.To() != *tx.To():
+		return "to"
+	case signedTx.Value().Cmp(tx.Value()) != 0:
+		return "value"
+	case !bytes.Equal(signedTx.Data(), tx.Data()):
+		return "data"
+	case signedTx.Gas() != tx.Gas():
+		return "gas"
+	// transaction EIP-1559 trả về fee cap làm gas price nên chỉ so gas price với transaction legacy
+	case tx.Type() != types.DynamicFeeTxType && signedTx.GasPrice().Cmp(tx.GasPrice()) != 0:
+		return "gas price"
+	case signedTx.GasFeeCap().Cmp(tx.GasFeeCap()) != 0:
+		return "gas fee cap"
+	case signedTx.GasTipCap().Cmp(tx.GasTipCap()) != 0:
+		return "gas tip cap"
+	case len(signedTx.AccessList()) != len(tx.AccessList()) ||
+		len(tx.AccessList()) > 0 && !reflect.DeepEqual(signedTx.AccessList(), tx.AccessList()):
+		return "access list"
+	}
+	return ""
+}
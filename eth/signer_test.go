@@ -0,0 +1,195 @@
+package eth_test
+
+import (
+	"crypto/ecdsa"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/google/uuid"
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeClef trả lời `account_signTransaction` như clef, `modify` sửa transaction trước khi ký
+type fakeClef struct {
+	key    *ecdsa.PrivateKey
+	modify func(args *apitypes.SendTxArgs)
+}
+
+func (c *fakeClef) SignTransaction(args apitypes.SendTxArgs) (map[string]hexutil.Bytes, error) {
+	if c.modify != nil {
+		c.modify(&args)
+	}
+	tx, err := types.SignTx(args.ToTransaction(), types.LatestSignerForChainID((*big.Int)(args.ChainID)), c.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]hexutil.Bytes{"raw": raw}, nil
+}
+
+func newExternalSigner(t *testing.T, clef *fakeClef, address common.Address) eth.Signer {
+	t.Helper()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", clef); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	t.Cleanup(server.Stop)
+
+	signer, err := eth.NewExternalSigner(httpServer.URL, address)
+	if err != nil {
+		t.Fatalf("NewExternalSigner: %v", err)
+	}
+	return signer
+}
+
+func TestExternalSignerVerifiesSignedTransaction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(97)
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	legacyTx := types.NewTx(&types.LegacyTx{
+		Nonce: 3, GasPrice: big.NewInt(10), Gas: 21000, To: &to, Value: big.NewInt(5), Data: []byte{1, 2},
+	})
+	dynamicTx := types.NewTx(&types.DynamicFeeTx{
+		ChainID: chainID, Nonce: 3, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(20), Gas: 21000, To: &to, Value: big.NewInt(5),
+	})
+
+	tests := []struct {
+		name    string
+		tx      *types.Transaction
+		key     *ecdsa.PrivateKey
+		modify  func(args *apitypes.SendTxArgs)
+		wantErr string
+	}{
+		{name: "legacy", tx: legacyTx},
+		{name: "dynamic fee", tx: dynamicTx},
+		{name: "other account", tx: legacyTx, key: otherKey, wantErr: "signed with"},
+		{name: "chain id", tx: legacyTx, modify: func(args *apitypes.SendTxArgs) {
+			args.ChainID = (*hexutil.Big)(big.NewInt(56))
+		}, wantErr: "sender"},
+		{name: "nonce", tx: legacyTx, modify: func(args *apitypes.SendTxArgs) { args.Nonce++ }, wantErr: "nonce"},
+		{name: "to", tx: legacyTx, modify: func(args *apitypes.SendTxArgs) {
+			other := common.NewMixedcaseAddress(common.HexToAddress("0xbb"))
+			args.To = &other
+		}, wantErr: "to"},
+		{name: "value", tx: legacyTx, modify: func(args *apitypes.SendTxArgs) {
+			args.Value = hexutil.Big(*big.NewInt(6))
+		}, wantErr: "value"},
+		{name: "data", tx: legacyTx, modify: func(args *apitypes.SendTxArgs) {
+			data := hexutil.Bytes{1, 3}
+			args.Data = &data
+		}, wantErr: "data"},
+		{name: "gas", tx: legacyTx, modify: func(args *apitypes.SendTxArgs) { args.Gas++ }, wantErr: "gas"},
+		{name: "gas price", tx: legacyTx, modify: func(args *apitypes.SendTxArgs) {
+			args.GasPrice = (*hexutil.Big)(big.NewInt(11))
+		}, wantErr: "gas price"},
+		{name: "gas fee cap", tx: dynamicTx, modify: func(args *apitypes.SendTxArgs) {
+			args.MaxFeePerGas = (*hexutil.Big)(big.NewInt(21))
+		}, wantErr: "gas fee cap"},
+		{name: "gas tip cap", tx: dynamicTx, modify: func(args *apitypes.SendTxArgs) {
+			args.MaxPriorityFeePerGas = (*hexutil.Big)(big.NewInt(2))
+		}, wantErr: "gas tip cap"},
+		{name: "access list", tx: dynamicTx, modify: func(args *apitypes.SendTxArgs) {
+			args.AccessList = &types.AccessList{{Address: to}}
+		}, wantErr: "access list"},
+		{name: "type", tx: dynamicTx, modify: func(args *apitypes.SendTxArgs) {
+			args.GasPrice = (*hexutil.Big)(big.NewInt(20))
+			args.MaxFeePerGas = nil
+			args.MaxPriorityFeePerGas = nil
+			args.AccessList = nil
+		}, wantErr: "type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clef := &fakeClef{key: key, modify: tt.modify}
+			if tt.key != nil {
+				clef.key = tt.key
+			}
+			signer := newExternalSigner(t, clef, address)
+
+			signedTx, err := signer.SignTx(ethtest.Context(t), tt.tx, chainID)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("SignTx: %v", err)
+				}
+				if signedTx.Hash() == tt.tx.Hash() || signedTx.Nonce() != tt.tx.Nonce() {
+					t.Fatalf("SignTx returned an unsigned or different transaction")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("SignTx error = %v, want error about %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrivateKeySigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := eth.NewPrivateKeySigner(key)
+	if signer.Address() != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("Address = %s, want key address", signer.Address().Hex())
+	}
+
+	chainID := big.NewInt(97)
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000})
+	signedTx, err := signer.SignTx(ethtest.Context(t), tx, chainID)
+	if err != nil {
+		t.Fatalf("SignTx: %v", err)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	if err != nil || sender != signer.Address() {
+		t.Fatalf("sender = %s, %v; want %s", sender.Hex(), err, signer.Address().Hex())
+	}
+
+	if _, err = eth.NewHexKeySigner("not a key"); err == nil {
+		t.Fatal("NewHexKeySigner accepted an invalid key")
+	}
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "key.json")
+	if err = ioutil.WriteFile(keyFile, keyJSON, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := eth.NewKeystoreSigner(keyFile, "secret")
+	if err != nil {
+		t.Fatalf("NewKeystoreSigner: %v", err)
+	}
+	if signer.Address() != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("Address = %s, want key address", signer.Address().Hex())
+	}
+	if _, err = eth.NewKeystoreSigner(keyFile, "wrong"); err == nil {
+		t.Fatal("NewKeystoreSigner accepted a wrong passphrase")
+	}
+}
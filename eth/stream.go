@@ -0,0 +1,234 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// Stream đọc log khớp một FilterQuery từ một block trong quá khứ rồi tiếp tục theo các block mới
+// phần lịch sử được đọc bằng Scanner tới block mới nhất trừ Confirmations, sau đó Stream chuyển sang
+// subscription `logs` (endpoint websocket) hoặc Watcher. Mỗi log, xác định bởi hash block và vị trí trong block,
+// chỉ được chuyển cho handler một lần
+//
+// khi có reorg, các log đã chuyển đi bị loại bỏ khỏi chain được chuyển lại với Removed là true, theo sau là các log
+// của chain mới. Các hàm Parse*Events chuyển log bị loại bỏ cho receiver `rollback` để hoàn tác và trả về
+// ErrRemovedEventNotHandled nếu không có `rollback`, hoặc đặt Confirmations đủ lớn để chỉ nhận log đã được xác nhận
+type Stream struct {
+	// Name là tên của Stream khi báo độ trễ cho LogObserver, rỗng là "stream"
+	Name string
+
+	// Confirmations là số block xác nhận trước khi log được chuyển đi
+	// khác 0 thì Stream chỉ dùng `eth_getLogs` theo chu kỳ Interval, không dùng subscription hay filter
+	// nên không nhận log bị loại bỏ do reorg ít hơn Confirmations block
+	Confirmations uint64
+
+	// ChunkSize là số block mỗi lần gọi `eth_getLogs`, 0 là DefaultScanChunkSize
+	ChunkSize uint64
+
+	// Interval là chu kỳ đọc log mới, 0 là DefaultWatchInterval
+	Interval time.Duration
+
+	client *Client
+	query  FilterQuery
+
+	mu     sync.Mutex
+	cursor *logCursor
+}
+
+// NewStream tạo Stream đọc log khớp Addresses và Topics của `query` từ block `fromBlock`
+func (c *Client) NewStream(query FilterQuery, fromBlock uint64) *Stream {
+	query.BlockHash = nil
+	query.FromBlock = BlockTag{}
+	query.ToBlock = BlockTag{}
+	return &Stream{
+		client: c,
+		query:  query,
+		cursor: newLogCursor(fromBlock),
+	}
+}
+
+// LastBlock trả về block cuối cùng mà mọi log tới block đó đã được chuyển đi, dùng để lưu tiến độ
+// khi chạy lại, tạo Stream mới từ LastBlock cộng 1
+func (s *Stream) LastBlock() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cursor.lastBlock()
+}
+
+// Run chuyển các log cho `handler` theo thứ tự block cho tới khi `ctx` bị huỷ hoặc có lỗi
+// nếu `handler` lỗi, Run trả về lỗi và lần Run sau chuyển lại các log của lần gọi `handler` bị lỗi
+// Run không dùng được đồng thời từ nhiều goroutine
+func (s *Stream) Run(ctx context.Context, handler LogHandler) error {
+	if s.Confirmations > 0 {
+		return s.poll(ctx, handler)
+	}
+	return s.tail(ctx, handler)
+}
+
+func (s *Stream) name() string {
+	if s.Name == "" {
+		return "stream"
+	}
+	return s.Name
+}
+
+func (s *Stream) interval() time.Duration {
+	if s.Interval <= 0 {
+		return DefaultWatchInterval
+	}
+	return s.Interval
+}
+
+// deliver chuyển các log chưa được chuyển trong `changes` cho `handler`
+// các log được lọc trên bản sao của cursor, bản sao chỉ thay cursor khi `handler` xử lý xong
+// nên nếu `handler` lỗi, chạy lại Stream chuyển lại các log này
+func (s *Stream) deliver(ctx context.Context, changes []*FilterChange, handler LogHandler) error {
+	s.mu.Lock()
+	cursor := s.cursor.fork()
+	s.mu.Unlock()
+
+	changes, err := cursor.filter(changes)
+	if err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		s.client.observeLogs(changes)
+		if err = handler(ctx, changes); err != nil {
+			return errors.Wrap(err, "stream handle logs error")
+		}
+	}
+
+	s.mu.Lock()
+	s.cursor = cursor
+	s.mu.Unlock()
+	return nil
+}
+
+// backfill đọc bằng Scanner các log từ block chưa đọc đủ tới block `to`
+func (s *Stream) backfill(ctx context.Context, to uint64, handler LogHandler) error {
+	s.mu.Lock()
+	from := s.cursor.next
+	s.mu.Unlock()
+	if from > to {
+		return nil
+	}
+
+	scanner := s.client.NewScanner(s.query, s.ChunkSize)
+	err := scanner.scan(ctx, from, to, func(ctx context.Context, changes []*FilterChange) error {
+		return s.deliver(ctx, changes, handler)
+	})
+	if err != nil {
+		return errors.Wrap(err, "stream backfill error")
+	}
+
+	// chỉ đánh dấu đã đọc đủ tới `to` khi handler đã xử lý xong mọi log của phần đọc bù
+	s.mu.Lock()
+	cursor := s.cursor.fork()
+	cursor.complete(to + 1)
+	s.cursor = cursor
+	s.mu.Unlock()
+	return nil
+}
+
+// poll đọc bù tới block mới nhất trừ Confirmations sau mỗi chu kỳ
+func (s *Stream) poll(ctx context.Context, handler LogHandler) error {
+	ticker := time.NewTicker(s.interval())
+	defer ticker.Stop()
+
+	for {
+		head, err := s.client.headBlock(ctx)
+		if err != nil {
+			return errors.Wrap(err, "stream poll error")
+		}
+		if head >= s.Confirmations {
+			if err = s.backfill(ctx, head-s.Confirmations, handler); err != nil {
+				return err
+			}
+			s.client.observeFilterLag(s.name(), head, s.LastBlock())
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "stream run error")
+		case <-ticker.C:
+		}
+	}
+}
+
+// tail đăng ký subscription trước rồi đọc bù tới block mới nhất, log subscription trả về trùng với phần đọc bù
+// bị bỏ qua. Khi subscription bị ngắt, Stream đăng ký lại và đọc bù phần bị mất
+// nếu endpoint không hỗ trợ subscription thì dùng Watcher
+func (s *Stream) tail(ctx context.Context, handler LogHandler) error {
+	for {
+		ch := make(chan types.Log)
+		sub, err := s.client.EthSubscribeLogs(ctx, s.query, ch)
+		if errors.Cause(err) == rpc.ErrNotificationsUnsupported {
+			return s.watch(ctx, handler)
+		}
+		if err != nil {
+			return errors.Wrap(err, "stream subscribe logs error")
+		}
+
+		err = s.consume(ctx, sub.Err(), ch, handler)
+		sub.Unsubscribe()
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "stream run error")
+		case <-time.After(s.interval()):
+		}
+	}
+}
+
+// consume đọc bù rồi chuyển các log của subscription, trả về nil khi subscription bị ngắt
+func (s *Stream) consume(ctx context.Context, subErr <-chan error, ch <-chan types.Log, handler LogHandler) error {
+	head, err := s.client.headBlock(ctx)
+	if err != nil {
+		return errors.Wrap(err, "stream tail error")
+	}
+	if err = s.backfill(ctx, head, handler); err != nil {
+		return err
+	}
+	s.client.observeFilterLag(s.name(), head, s.LastBlock())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "stream run error")
+		case <-subErr:
+			return nil
+		case log := <-ch:
+			changes := []*FilterChange{NewFilterChange(&log)}
+			s.client.observeEvents(changes)
+			if err = s.deliver(ctx, changes, handler); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watch tạo Watcher trước rồi đọc bù tới block mới nhất lúc tạo Watcher, sau đó chuyển các log của Watcher
+func (s *Stream) watch(ctx context.Context, handler LogHandler) error {
+	w, err := s.client.NewWatcher(ctx, s.query, s.interval())
+	if err != nil {
+		return errors.Wrap(err, "stream create watcher error")
+	}
+	defer w.Close(context.Background())
+	w.Name = s.name()
+	w.observe = false
+
+	if err = s.backfill(ctx, w.LastBlock(), handler); err != nil {
+		return err
+	}
+	return w.Watch(ctx, func(ctx context.Context, changes []*FilterChange) error {
+		return s.deliver(ctx, changes, handler)
+	})
+}
@@ -0,0 +1,218 @@
+package eth_test
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// forkLog là log ở block `block` của chain mới sau reorg, có hash block khác với ethtest.BlockHash
+func forkLog(block uint64, count int64) types.Log {
+	log := ethtest.EventLog(counterAddress, counterABI, "Counted", alice, big.NewInt(count))
+	log.BlockNumber = block
+	log.BlockHash = common.BigToHash(new(big.Int).SetUint64(0xf000 + block))
+	log.TxHash = common.BigToHash(big.NewInt(count))
+	return log
+}
+
+// label mô tả log đã chuyển đi: số block, thêm "f" nếu log thuộc chain mới sau reorg, thêm "-" phía trước nếu log bị loại bỏ
+func label(change *eth.FilterChange) string {
+	number, _ := change.ParseBlockNumber()
+	s := fmt.Sprint(number)
+	if change.BlockHash != ethtest.BlockHash(number) {
+		s += "f"
+	}
+	if change.Removed {
+		s = "-" + s
+	}
+	return s
+}
+
+func addCounted(node *ethtest.Node, blocks ...uint64) {
+	for _, block := range blocks {
+		node.AddEvent(block, counterAddress, counterABI, "Counted", alice, big.NewInt(int64(block)))
+	}
+}
+
+func TestStreamDeliversEachLogOnce(t *testing.T) {
+	type step struct {
+		// after là số log đã chuyển đi trước khi chạy `do`
+		after int
+		do    func(node *ethtest.Node)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(node *ethtest.Node)
+		getLogs func(node *ethtest.Node)
+		steps   []step
+		want    []string
+	}{
+		{
+			name:  "backfill to tail handoff",
+			setup: func(node *ethtest.Node) { addCounted(node, 1, 2, 3) },
+			steps: []step{{after: 3, do: func(node *ethtest.Node) { addCounted(node, 4, 5) }}},
+			want:  []string{"1", "2", "3", "4", "5"},
+		},
+		{
+			name:  "filter expiry",
+			setup: func(node *ethtest.Node) { addCounted(node, 1) },
+			steps: []step{
+				{after: 1, do: func(node *ethtest.Node) { addCounted(node, 2) }},
+				{after: 2, do: func(node *ethtest.Node) {
+					node.ExpireFilters()
+					addCounted(node, 3, 4)
+				}},
+				{after: 4, do: func(node *ethtest.Node) { addCounted(node, 5) }},
+			},
+			want: []string{"1", "2", "3", "4", "5"},
+		},
+		{
+			name:  "reorg with removed logs",
+			setup: func(node *ethtest.Node) { addCounted(node, 1) },
+			steps: []step{
+				{after: 1, do: func(node *ethtest.Node) { addCounted(node, 2, 3) }},
+				{after: 3, do: func(node *ethtest.Node) { node.Reorg(2, forkLog(2, 20)) }},
+				{after: 6, do: func(node *ethtest.Node) { addCounted(node, 4) }},
+			},
+			want: []string{"1", "2", "3", "-2", "-3", "2f", "4"},
+		},
+		{
+			name:  "reorg while backfilling",
+			setup: func(node *ethtest.Node) { addCounted(node, 1, 2) },
+			getLogs: func(node *ethtest.Node) {
+				node.Reorg(2, forkLog(2, 20))
+			},
+			steps: []step{{after: 2, do: func(node *ethtest.Node) { addCounted(node, 3) }}},
+			want:  []string{"1", "2f", "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var node *ethtest.Node
+			reorged := false
+			beforeGetLogs := eth.Interceptor(func(ctx context.Context, call *eth.RPCCall, invoker eth.Invoker) error {
+				if call.Method == "eth_getLogs" && tt.getLogs != nil && !reorged {
+					reorged = true
+					tt.getLogs(node)
+				}
+				return invoker(ctx, call)
+			})
+			client, node := ethtest.NewClient(t, eth.WithInterceptors(beforeGetLogs))
+			tt.setup(node)
+
+			ctx, cancel := context.WithCancel(ethtest.Context(t))
+			defer cancel()
+
+			stream := client.NewStream(eth.FilterQuery{Addresses: []common.Address{counterAddress}}, 1)
+			stream.Interval = time.Millisecond
+
+			var got []string
+			applied := make(map[string]int)
+			next := 0
+			err := stream.Run(ctx, func(ctx context.Context, changes []*eth.FilterChange) error {
+				for _, change := range changes {
+					got = append(got, label(change))
+
+					key := change.BlockHash.Hex() + "/" + change.LogIndex
+					if change.Removed {
+						applied[key]--
+					} else {
+						applied[key]++
+					}
+					if applied[key] < 0 || applied[key] > 1 {
+						t.Errorf("log %s delivered %d times", key, applied[key])
+					}
+				}
+				for next < len(tt.steps) && len(got) >= tt.steps[next].after {
+					tt.steps[next].do(node)
+					next++
+				}
+				if len(got) >= len(tt.want) {
+					cancel()
+				}
+				return nil
+			})
+			if !stderrors.Is(err, context.Canceled) {
+				t.Fatalf("Run: %v, delivered %v", err, got)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("delivered %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamResumesAfterHandlerError(t *testing.T) {
+	tests := []struct {
+		name          string
+		confirmations uint64
+	}{
+		{name: "watcher"},
+		{name: "confirmations", confirmations: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, node := ethtest.NewClient(t)
+			addCounted(node, 1)
+			node.SetHead(1 + tt.confirmations)
+
+			stream := client.NewStream(eth.FilterQuery{Addresses: []common.Address{counterAddress}}, 1)
+			stream.Interval = time.Millisecond
+			stream.Confirmations = tt.confirmations
+
+			// handler lỗi khi nhận log của block 2 lần đầu tiên
+			handlerErr := stderrors.New("database is down")
+			failed := false
+			var got []string
+			run := func(ctx context.Context, cancel context.CancelFunc) error {
+				return stream.Run(ctx, func(ctx context.Context, changes []*eth.FilterChange) error {
+					for _, change := range changes {
+						if label(change) == "2" && !failed {
+							failed = true
+							return handlerErr
+						}
+					}
+					for _, change := range changes {
+						got = append(got, label(change))
+					}
+					if len(got) == 1 {
+						addCounted(node, 2)
+						node.SetHead(2 + tt.confirmations)
+					}
+					if len(got) >= 2 {
+						cancel()
+					}
+					return nil
+				})
+			}
+
+			ctx, cancel := context.WithCancel(ethtest.Context(t))
+			defer cancel()
+			if err := run(ctx, cancel); !stderrors.Is(err, handlerErr) {
+				t.Fatalf("first Run: %v, want handler error", err)
+			}
+			if last := stream.LastBlock(); last != 1 {
+				t.Fatalf("LastBlock after handler error = %d, want 1", last)
+			}
+
+			// chạy lại Stream chuyển lại log của block 2 mà handler chưa xử lý được, không chuyển lại block 1
+			if err := run(ctx, cancel); !stderrors.Is(err, context.Canceled) {
+				t.Fatalf("second Run: %v, delivered %v", err, got)
+			}
+			if want := []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+				t.Fatalf("delivered %v, want %v", got, want)
+			}
+		})
+	}
+}
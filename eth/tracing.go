@@ -0,0 +1,77 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName là tên instrumentation của các span do thư viện tạo ra
+const tracerName = "github.com/adene-develop/adene-goeth"
+
+// WithTracerProvider bật tracing OpenTelemetry: mỗi lời gọi của contract wrapper tạo một span
+// với tên contract, tên hàm và địa chỉ contract, mỗi request JSON-RPC (endpoint http(s)) tạo một span con
+// span cha được lấy từ context của người gọi. Mặc định client không tạo span
+// span JSON-RPC do transport http tạo giống interceptor nên client websocket, IPC chỉ có span của contract wrapper
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *Client) {
+		c.tracer = provider.Tracer(tracerName)
+	}
+}
+
+type contractSpanKey struct{}
+
+// StartSpan bắt đầu span cho lời gọi hàm `function` của contract `contract` tại `address`, dùng trong các contract wrapper
+// người gọi phải kết thúc span bằng span.End(), lỗi của client trong context trả về được ghi vào span
+// nếu client không bật tracing, context giữ nguyên và span không ghi gì
+func (c *Client) StartSpan(ctx context.Context, contract string, function string, address common.Address) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+
+	ctx, span := c.tracer.Start(ctx, contract+"."+function,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("contract.name", contract),
+			attribute.String("contract.function", function),
+			attribute.String("contract.address", address.Hex()),
+		))
+	return context.WithValue(ctx, contractSpanKey{}, span), span
+}
+
+// RecordSpanError ghi `err` vào span của contract wrapper trong `ctx` nếu có rồi trả về `err`
+// dùng trong contract wrapper cho lỗi trả về trước khi gọi client, ví dụ lỗi kiểm tra tham số
+func RecordSpanError(ctx context.Context, err error) error {
+	recordSpanError(ctx, err)
+	return err
+}
+
+// recordSpanError ghi `err` vào span của contract wrapper trong `ctx` nếu có
+func recordSpanError(ctx context.Context, err error) {
+	span, ok := ctx.Value(contractSpanKey{}).(trace.Span)
+	if !ok || err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// tracingInterceptor tạo span con cho mỗi lời gọi JSON-RPC
+func (c *Client) tracingInterceptor(ctx context.Context, call *RPCCall, invoker Invoker) error {
+	ctx, span := c.tracer.Start(ctx, call.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "jsonrpc"),
+			attribute.String("rpc.method", call.Method),
+		))
+	defer span.End()
+
+	err := invoker(ctx, call)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
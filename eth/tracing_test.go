@@ -0,0 +1,206 @@
+package eth_test
+
+import (
+	"context"
+	stderrors "errors"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+// spanRecorder là trace.TracerProvider ghi lại các span được tạo
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+// recordedSpan là span đã ghi của spanRecorder
+type recordedSpan struct {
+	recorder   *spanRecorder
+	name       string
+	parent     *recordedSpan
+	kind       trace.SpanKind
+	attributes map[attribute.Key]string
+	errs       []error
+	status     codes.Code
+	ended      bool
+}
+
+func (r *spanRecorder) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return r
+}
+
+func (r *spanRecorder) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	config := trace.NewSpanStartConfig(opts...)
+	span := &recordedSpan{recorder: r, name: name, kind: config.SpanKind(), attributes: map[attribute.Key]string{}}
+	if parent, ok := trace.SpanFromContext(ctx).(*recordedSpan); ok {
+		span.parent = parent
+	}
+	for _, kv := range config.Attributes() {
+		span.attributes[kv.Key] = kv.Value.Emit()
+	}
+
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+// span trả về span tên `name`, nil nếu không có
+func (r *spanRecorder) span(name string) *recordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, span := range r.spans {
+		if span.name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func (s *recordedSpan) End(...trace.SpanEndOption) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.ended = true
+}
+
+func (s *recordedSpan) AddEvent(string, ...trace.EventOption) {}
+
+func (s *recordedSpan) IsRecording() bool { return true }
+
+func (s *recordedSpan) RecordError(err error, _ ...trace.EventOption) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.errs = append(s.errs, err)
+}
+
+func (s *recordedSpan) SpanContext() trace.SpanContext { return trace.SpanContext{} }
+
+func (s *recordedSpan) SetStatus(code codes.Code, _ string) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.status = code
+}
+
+func (s *recordedSpan) SetName(name string) { s.name = name }
+
+func (s *recordedSpan) SetAttributes(kv ...attribute.KeyValue) {}
+
+func (s *recordedSpan) TracerProvider() trace.TracerProvider { return s.recorder }
+
+func TestClientStartSpanWithoutTracer(t *testing.T) {
+	client, _ := ethtest.NewClient(t)
+	ctx := context.Background()
+
+	spanCtx, span := client.StartSpan(ctx, "counter", "count", counterAddress)
+	defer span.End()
+	if spanCtx != ctx || span.IsRecording() {
+		t.Fatal("client without tracer started a recording span")
+	}
+	// không có span nên lỗi chỉ được trả về
+	err := stderrors.New("invalid argument")
+	if got := eth.RecordSpanError(spanCtx, err); got != err {
+		t.Fatalf("RecordSpanError = %v, want %v", got, err)
+	}
+}
+
+func TestClientTracingSpans(t *testing.T) {
+	tests := []struct {
+		name       string
+		revert     bool
+		wantStatus codes.Code
+	}{
+		{name: "success", wantStatus: codes.Unset},
+		{name: "revert", revert: true, wantStatus: codes.Error},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := &spanRecorder{}
+			client, node := ethtest.NewClient(t, eth.WithTracerProvider(recorder))
+			if test.revert {
+				node.OnCallRevert(counterAddress, counterABI, "count", []interface{}{alice}, "blocked")
+			} else {
+				node.OnCall(counterAddress, counterABI, "count", []interface{}{alice}, big.NewInt(7))
+			}
+
+			ctx, span := client.StartSpan(ethtest.Context(t), "counter", "count", counterAddress)
+			var result struct {
+				Count *big.Int
+			}
+			err := client.CallContractViewFunction(ctx, counterABI, counterAddress, &result, "count", alice)
+			span.End()
+			if (err != nil) != test.revert {
+				t.Fatalf("CallContractViewFunction error = %v, want revert %v", err, test.revert)
+			}
+
+			contractSpan := recorder.span("counter.count")
+			if contractSpan == nil || !contractSpan.ended || contractSpan.kind != trace.SpanKindClient {
+				t.Fatalf("contract span = %+v, want an ended client span", contractSpan)
+			}
+			wantAttributes := map[attribute.Key]string{
+				"contract.name":     "counter",
+				"contract.function": "count",
+				"contract.address":  counterAddress.Hex(),
+			}
+			for key, want := range wantAttributes {
+				if got := contractSpan.attributes[key]; got != want {
+					t.Fatalf("contract span %s = %q, want %q", key, got, want)
+				}
+			}
+			if contractSpan.status != test.wantStatus || (len(contractSpan.errs) > 0) != test.revert {
+				t.Fatalf("contract span status = %v with errors %v, want %v", contractSpan.status, contractSpan.errs, test.wantStatus)
+			}
+
+			// request JSON-RPC là span con của span contract
+			rpcSpan := recorder.span("eth_call")
+			if rpcSpan == nil || rpcSpan.parent != contractSpan || !rpcSpan.ended {
+				t.Fatalf("eth_call span = %+v, want an ended child of the contract span", rpcSpan)
+			}
+			if rpcSpan.attributes["rpc.system"] != "jsonrpc" || rpcSpan.attributes["rpc.method"] != "eth_call" {
+				t.Fatalf("eth_call span attributes = %v", rpcSpan.attributes)
+			}
+		})
+	}
+}
+
+func TestClientTracingRPCError(t *testing.T) {
+	recorder := &spanRecorder{}
+	client, node := ethtest.NewClient(t, eth.WithTracerProvider(recorder), eth.WithRetryPolicy(eth.RetryPolicy{MaxAttempts: 1}))
+	node.Inject("eth_chainId", ethtest.Fault{Error: &eth.RPCError{Code: ethtest.CodeServerError, Message: "header not found"}})
+
+	if _, err := client.ChainID(ethtest.Context(t)); err == nil {
+		t.Fatal("ChainID succeeded on a failing node")
+	}
+	span := recorder.span("eth_chainId")
+	if span == nil || span.parent != nil || span.status != codes.Error || len(span.errs) != 1 {
+		t.Fatalf("eth_chainId span = %+v, want a root span with the error", span)
+	}
+}
+
+func TestRecordSpanError(t *testing.T) {
+	recorder := &spanRecorder{}
+	client, _ := ethtest.NewClient(t, eth.WithTracerProvider(recorder))
+
+	ctx, span := client.StartSpan(ethtest.Context(t), "counter", "increase", counterAddress)
+	err := stderrors.New("invalid amount")
+	if got := eth.RecordSpanError(ctx, err); got != err {
+		t.Fatalf("RecordSpanError = %v, want %v", got, err)
+	}
+	// lỗi nil không đổi trạng thái span
+	if got := eth.RecordSpanError(ctx, nil); got != nil {
+		t.Fatalf("RecordSpanError(nil) = %v", got)
+	}
+	span.End()
+
+	contractSpan := recorder.span("counter.increase")
+	if contractSpan.status != codes.Error || len(contractSpan.errs) != 1 || contractSpan.errs[0] != err {
+		t.Fatalf("contract span status = %v with errors %v, want the recorded error", contractSpan.status, contractSpan.errs)
+	}
+}
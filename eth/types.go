@@ -3,6 +3,8 @@ package eth
 import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
 )
 
 type CreateFilter struct {
@@ -23,6 +25,44 @@ type FilterChange struct {
 	Removed          bool           `json:"removed"`
 }
 
+// EventID trả về topic đầu tiên của log là id của event
+// log không có topic, ví dụ log của event anonymous hoặc LOG0, trả về hash rỗng
 func (f *FilterChange) EventID() common.Hash {
+	if len(f.Topics) == 0 {
+		return common.Hash{}
+	}
 	return f.Topics[0]
 }
+
+// ParseBlockNumber trả về số block của log
+func (f *FilterChange) ParseBlockNumber() (uint64, error) {
+	number, err := hexutil.DecodeUint64(f.BlockNumber)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid log block number %s", f.BlockNumber)
+	}
+	return number, nil
+}
+
+// ParseLogIndex trả về vị trí của log trong block
+func (f *FilterChange) ParseLogIndex() (uint64, error) {
+	index, err := hexutil.DecodeUint64(f.LogIndex)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid log index %s", f.LogIndex)
+	}
+	return index, nil
+}
+
+// NewFilterChange chuyển log trong receipt hoặc kết quả `eth_getLogs` thành FilterChange
+func NewFilterChange(log *types.Log) *FilterChange {
+	return &FilterChange{
+		Address:          log.Address,
+		Topics:           log.Topics,
+		Data:             log.Data,
+		BlockNumber:      hexutil.EncodeUint64(log.BlockNumber),
+		TransactionHash:  log.TxHash,
+		TransactionIndex: hexutil.EncodeUint64(uint64(log.TxIndex)),
+		BlockHash:        log.BlockHash,
+		LogIndex:         hexutil.EncodeUint64(uint64(log.Index)),
+		Removed:          log.Removed,
+	}
+}
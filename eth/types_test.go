@@ -0,0 +1,30 @@
+package eth_test
+
+import (
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"testing"
+)
+
+func TestFilterChangeEventID(t *testing.T) {
+	counted := counterABI.Events["Counted"].ID
+
+	tests := []struct {
+		name   string
+		topics []common.Hash
+		want   common.Hash
+	}{
+		{name: "event", topics: []common.Hash{counted, common.BytesToHash(alice.Bytes())}, want: counted},
+		{name: "no topics", want: common.Hash{}},
+		{name: "empty topics", topics: []common.Hash{}, want: common.Hash{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			change := &eth.FilterChange{Address: counterAddress, Topics: test.topics}
+			if got := change.EventID(); got != test.want {
+				t.Fatalf("EventID = %s, want %s", got.Hex(), test.want.Hex())
+			}
+		})
+	}
+}
@@ -0,0 +1,374 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval là chu kỳ mặc định đọc log mới của Watcher, bằng thời gian ra block của bsc
+const DefaultWatchInterval = 3 * time.Second
+
+// ErrWatcherClosed được trả về khi dùng Watcher đã đóng
+var ErrWatcherClosed = errors.New("watcher is closed")
+
+// logKey xác định một log trên chain
+type logKey struct {
+	blockHash common.Hash
+	logIndex  string
+}
+
+func newLogKey(change *FilterChange) logKey {
+	return logKey{blockHash: change.BlockHash, logIndex: change.LogIndex}
+}
+
+// logCursorDepth là số block trước block chưa đọc đủ mà logCursor còn nhớ các log đã trả về,
+// log của chain mới ở các block này sau reorg vẫn được trả về, log ở block cũ hơn bị bỏ
+const logCursorDepth = 128
+
+// logCursor bỏ các log đã trả về khi cùng một khoảng block được đọc nhiều lần
+// mỗi log, xác định bởi hash block và vị trí trong block, chỉ được trả về một lần
+type logCursor struct {
+	start uint64
+	next  uint64
+
+	// delivered là các log đã trả về theo số block, chỉ giữ các block từ next trừ logCursorDepth
+	delivered map[uint64]map[logKey]struct{}
+}
+
+func newLogCursor(next uint64) *logCursor {
+	return &logCursor{
+		start:     next,
+		next:      next,
+		delivered: make(map[uint64]map[logKey]struct{}),
+	}
+}
+
+// fork trả về bản sao của cursor, dùng để lọc log trước khi chắc chắn các log đã được xử lý
+// bản sao dùng chung map log của từng block với cursor gốc, filter sao chép map của block trước khi ghi
+func (c *logCursor) fork() *logCursor {
+	delivered := make(map[uint64]map[logKey]struct{}, len(c.delivered))
+	for number, keys := range c.delivered {
+		delivered[number] = keys
+	}
+	return &logCursor{
+		start:     c.start,
+		next:      c.next,
+		delivered: delivered,
+	}
+}
+
+// filter trả về các log chưa được trả về trong `changes`, `changes` phải theo thứ tự block
+// nên khi thấy log ở một block thì các block trước đó được coi là đã đủ log
+// log bị loại bỏ do reorg (Removed là true) chỉ được trả về nếu log đó đã được trả về trước đó,
+// log của chain mới ở các block đã đọc được trả về nếu chưa được trả về
+// filter không sửa map log đã có của block nào nên không làm thay đổi cursor đã fork ra cursor này
+func (c *logCursor) filter(changes []*FilterChange) ([]*FilterChange, error) {
+	delivered := make([]*FilterChange, 0, len(changes))
+	copied := make(map[uint64]bool)
+	for _, change := range changes {
+		number, err := change.ParseBlockNumber()
+		if err != nil {
+			return nil, err
+		}
+		if number < c.start {
+			continue
+		}
+
+		key := newLogKey(change)
+		keys := c.delivered[number]
+		_, ok := keys[key]
+		if change.Removed {
+			// log chưa từng được trả về, ví dụ log của chain cũ mà phần đọc bù không thấy, không có gì để hoàn tác
+			if !ok {
+				continue
+			}
+			keys = c.writableKeys(number, copied)
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(c.delivered, number)
+				delete(copied, number)
+			}
+			delivered = append(delivered, change)
+			continue
+		}
+
+		if ok || number+logCursorDepth < c.next {
+			continue
+		}
+		keys = c.writableKeys(number, copied)
+		keys[key] = struct{}{}
+		c.complete(number)
+		delivered = append(delivered, change)
+	}
+	return delivered, nil
+}
+
+// writableKeys trả về bản sao của map log của block `number` để ghi, mỗi block chỉ được sao chép một lần
+// trong một lần filter, `copied` là các block đã sao chép
+func (c *logCursor) writableKeys(number uint64, copied map[uint64]bool) map[logKey]struct{} {
+	if copied[number] {
+		return c.delivered[number]
+	}
+	keys := make(map[logKey]struct{}, len(c.delivered[number])+1)
+	for key := range c.delivered[number] {
+		keys[key] = struct{}{}
+	}
+	c.delivered[number] = keys
+	copied[number] = true
+	return keys
+}
+
+// complete đánh dấu mọi log ở block nhỏ hơn `block` đã được trả về
+func (c *logCursor) complete(block uint64) {
+	if block <= c.next {
+		return
+	}
+	c.next = block
+	for number := range c.delivered {
+		if number+logCursorDepth < block {
+			delete(c.delivered, number)
+		}
+	}
+}
+
+// lastBlock trả về block cuối cùng đã trả về đủ log
+func (c *logCursor) lastBlock() uint64 {
+	if c.next == 0 {
+		return 0
+	}
+	return c.next - 1
+}
+
+// Watcher đọc log mới khớp một FilterQuery bằng filter trên node và tự quản lý filter đó
+// khi node xoá filter (filter lâu không được đọc hoặc node khởi động lại) hoặc đọc filter lỗi, Watcher tạo lại filter
+// và đọc bù bằng `eth_getLogs` từ block cuối cùng đã thấy nên không mất và không lặp log
+// người gọi phải đóng Watcher bằng Close để xoá filter trên node
+type Watcher struct {
+	// Name là tên của Watcher khi báo độ trễ cho LogObserver, rỗng là "watcher"
+	Name string
+
+	client   *Client
+	query    FilterQuery
+	interval time.Duration
+
+	// observe là false khi Watcher nằm trong Stream, Stream tự báo các log đã chuyển đi
+	observe bool
+
+	mu       sync.Mutex
+	filterID string
+	stale    bool
+	closed   bool
+	quit     chan struct{}
+	cursor   *logCursor
+}
+
+// NewWatcher tạo filter khớp `query` trên node và trả về Watcher đọc log mới sau mỗi `interval`
+// `interval` bằng 0 là DefaultWatchInterval. Chỉ dùng được với client có kết nối JSON-RPC
+func (c *Client) NewWatcher(ctx context.Context, query FilterQuery, interval time.Duration) (*Watcher, error) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	w := &Watcher{
+		client:   c,
+		query:    query,
+		interval: interval,
+		observe:  true,
+		quit:     make(chan struct{}),
+	}
+
+	// tạo filter trước rồi mới đọc block mới nhất giống reinstall: filter thấy mọi block sau block mới nhất đã đọc,
+	// nếu đọc block trước thì log của các block được tạo giữa hai lời gọi bị mất
+	var err error
+	w.filterID, err = c.EthNewFilterQuery(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "watcher install filter error")
+	}
+	head, err := w.client.headBlock(ctx)
+	if err != nil {
+		c.EthUninstallFilter(ctx, w.filterID)
+		return nil, err
+	}
+	w.cursor = newLogCursor(head + 1)
+	return w, nil
+}
+
+// FilterID trả về id của filter hiện tại trên node, thay đổi mỗi khi Watcher tạo lại filter
+func (w *Watcher) FilterID() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.filterID
+}
+
+// LastBlock trả về block cuối cùng mà mọi log tới block đó đã được trả về
+// khi mới tạo Watcher là block mới nhất đọc được ngay sau khi tạo filter
+func (w *Watcher) LastBlock() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.cursor.lastBlock()
+}
+
+// Poll đọc các log mới từ lần đọc trước, tạo lại filter và đọc bù nếu node đã xoá filter
+// khi đọc filter lỗi, các log trong response bị lỗi có thể đã bị node bỏ nên Poll cũng tạo lại filter và đọc bù
+// nếu không tạo lại được filter thì lỗi được trả về và lần Poll sau thử lại
+func (w *Watcher) Poll(ctx context.Context) ([]*FilterChange, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changes, err := w.poll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if w.observe {
+		w.client.observeLogs(changes)
+	}
+	if w.client.logObserver != nil {
+		// lỗi khi đọc block mới nhất bị bỏ qua vì không ảnh hưởng tới việc đọc log
+		if head, err := w.client.blockNumber(ctx); err == nil {
+			w.client.observeFilterLag(w.name(), head, w.cursor.lastBlock())
+		}
+	}
+	return changes, nil
+}
+
+func (w *Watcher) name() string {
+	if w.Name == "" {
+		return "watcher"
+	}
+	return w.Name
+}
+
+func (w *Watcher) poll(ctx context.Context) ([]*FilterChange, error) {
+	if w.closed {
+		return nil, ErrWatcherClosed
+	}
+	if w.stale {
+		return w.reinstall(ctx)
+	}
+
+	changes, err := w.client.EthGetFilterChanges(ctx, w.filterID)
+	if err != nil {
+		w.stale = true
+		changes, err = w.reinstall(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "watcher get filter changes error")
+		}
+		return changes, nil
+	}
+	return w.cursor.filter(changes)
+}
+
+// Watch gọi Poll sau mỗi chu kỳ và chuyển các log mới cho `handler` cho tới khi `ctx` bị huỷ,
+// Watcher bị đóng hoặc có lỗi. Log đã chuyển cho `handler` không được trả về lại kể cả khi `handler` lỗi
+func (w *Watcher) Watch(ctx context.Context, handler LogHandler) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		changes, err := w.Poll(ctx)
+		if err == ErrWatcherClosed {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(changes) > 0 {
+			if err = handler(ctx, changes); err != nil {
+				return errors.Wrap(err, "watcher handle changes error")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "watcher watch error")
+		case <-w.quit:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close dừng Watch và xoá filter trên node, filter đã bị node xoá trước đó không bị coi là lỗi
+func (w *Watcher) Close(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	close(w.quit)
+
+	if w.filterID == "" {
+		return nil
+	}
+	err := w.client.EthUninstallFilter(ctx, w.filterID)
+	if err != nil && errors.Cause(err) != ErrFilterNotRemoved && !IsFilterNotFoundError(err) {
+		return errors.Wrap(err, "watcher uninstall filter error")
+	}
+	return nil
+}
+
+// reinstall tạo lại filter rồi đọc bù bằng Scanner các log từ block chưa đọc đủ tới block mới nhất
+// log filter mới trả về trùng với phần đọc bù bị bỏ qua bởi logCursor
+// Watcher chỉ hết stale khi đọc bù xong, nếu lỗi thì lần Poll sau tạo lại filter và đọc bù lại
+func (w *Watcher) reinstall(ctx context.Context) ([]*FilterChange, error) {
+	// filter cũ có thể vẫn còn trên node, xoá để không để lại filter thừa, lỗi không ảnh hưởng tới việc tạo lại filter
+	if w.filterID != "" {
+		w.client.EthUninstallFilter(ctx, w.filterID)
+		w.filterID = ""
+	}
+
+	filterID, err := w.client.EthNewFilterQuery(ctx, w.query)
+	if err != nil {
+		return nil, errors.Wrap(err, "watcher reinstall filter error")
+	}
+	w.filterID = filterID
+
+	head, err := w.client.headBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if to := w.query.ToBlock.Number(); to != nil && to.IsUint64() && to.Uint64() < head {
+		head = to.Uint64()
+	}
+	if head < w.cursor.next {
+		w.stale = false
+		return nil, nil
+	}
+
+	var missed []*FilterChange
+	err = w.client.NewScanner(w.query, 0).scan(ctx, w.cursor.next, head, func(ctx context.Context, changes []*FilterChange) error {
+		missed = append(missed, changes...)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "watcher get missed logs error")
+	}
+
+	delivered, err := w.cursor.filter(missed)
+	if err != nil {
+		return nil, err
+	}
+	w.cursor.complete(head + 1)
+	w.stale = false
+	return delivered, nil
+}
+
+// headBlock trả về số của block mới nhất, có retry
+func (c *Client) headBlock(ctx context.Context) (uint64, error) {
+	var head uint64
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		head, err = c.blockNumber(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "client get block number error")
+	}
+	return head, nil
+}
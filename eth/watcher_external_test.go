@@ -0,0 +1,125 @@
+package eth_test
+
+import (
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatcherPollReinstallsExpiredFilter(t *testing.T) {
+	client, node := ethtest.NewClient(t)
+	ctx := ethtest.Context(t)
+
+	watcher, err := client.NewWatcher(ctx, eth.FilterQuery{Addresses: []common.Address{counterAddress}}, time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+	defer watcher.Close(ctx)
+
+	node.AddEvent(1, counterAddress, counterABI, "Counted", alice, big.NewInt(1))
+	changes, err := watcher.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("first poll got %d logs, want 1", len(changes))
+	}
+
+	// log của block 2 chỉ đọc được qua eth_getLogs vì filter đã bị node xoá
+	node.ExpireFilters()
+	node.AddEvent(2, counterAddress, counterABI, "Counted", alice, big.NewInt(2))
+	changes, err = watcher.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll after expiry: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("poll after expiry got %d logs, want 1", len(changes))
+	}
+	if number, _ := changes[0].ParseBlockNumber(); number != 2 {
+		t.Fatalf("log at block %d, want 2", number)
+	}
+	if got := len(node.Filters()); got != 1 {
+		t.Fatalf("node has %d filters, want 1", got)
+	}
+
+	if err = watcher.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := len(node.Filters()); got != 0 {
+		t.Fatalf("node has %d filters after Close, want 0", got)
+	}
+}
+
+func TestWatcherPollRetriesAfterError(t *testing.T) {
+	unavailable := ethtest.Fault{HTTPStatus: http.StatusServiceUnavailable, Times: 1}
+
+	tests := []struct {
+		name string
+		// faults là lỗi node trả về cho từng method ở lần Poll lỗi
+		faults map[string]ethtest.Fault
+		// wantErr là true nếu lần Poll lỗi trả về lỗi, lần Poll sau phải tạo lại filter và đọc bù
+		wantErr bool
+	}{
+		{name: "filter changes error", faults: map[string]ethtest.Fault{"eth_getFilterChanges": unavailable}},
+		{name: "reinstall error", faults: map[string]ethtest.Fault{"eth_getFilterChanges": unavailable, "eth_newFilter": unavailable}, wantErr: true},
+		{name: "missed logs error", faults: map[string]ethtest.Fault{"eth_getFilterChanges": unavailable, "eth_getLogs": unavailable}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, node := ethtest.NewClient(t, eth.WithRetryPolicy(eth.RetryPolicy{MaxAttempts: 1}))
+			ctx := ethtest.Context(t)
+
+			watcher, err := client.NewWatcher(ctx, eth.FilterQuery{Addresses: []common.Address{counterAddress}}, time.Millisecond)
+			if err != nil {
+				t.Fatalf("NewWatcher: %v", err)
+			}
+			defer watcher.Close(ctx)
+
+			node.AddEvent(1, counterAddress, counterABI, "Counted", alice, big.NewInt(1))
+			if changes, err := watcher.Poll(ctx); err != nil || len(changes) != 1 {
+				t.Fatalf("first poll = %d logs, %v; want 1 log", len(changes), err)
+			}
+
+			node.AddEvent(2, counterAddress, counterABI, "Counted", alice, big.NewInt(2))
+			for method, fault := range test.faults {
+				node.Inject(method, fault)
+			}
+			var blocks []uint64
+			changes, err := watcher.Poll(ctx)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("poll with faults error = %v, want error %v", err, test.wantErr)
+			}
+			if err != nil {
+				node.AddEvent(3, counterAddress, counterABI, "Counted", alice, big.NewInt(3))
+				if changes, err = watcher.Poll(ctx); err != nil {
+					t.Fatalf("poll after error: %v", err)
+				}
+			}
+			for _, change := range changes {
+				number, _ := change.ParseBlockNumber()
+				blocks = append(blocks, number)
+			}
+
+			// log của block 2 không bị mất, log của block 1 không bị trả về lại
+			want := []uint64{2}
+			if test.wantErr {
+				want = []uint64{2, 3}
+			}
+			if !reflect.DeepEqual(blocks, want) {
+				t.Fatalf("logs at blocks %v, want %v", blocks, want)
+			}
+			if got := len(node.Filters()); got != 1 {
+				t.Fatalf("node has %d filters after reinstall, want 1", got)
+			}
+			if got := watcher.FilterID(); got == "" || node.RequestCount("eth_newFilter") < 2 {
+				t.Fatalf("filter %q was not reinstalled", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestLogCursorFilter(t *testing.T) {
+	forks := make(map[common.Hash]string)
+	change := func(block uint64, fork string, removed bool) *FilterChange {
+		hash := crypto.Keccak256Hash([]byte(fork), new(big.Int).SetUint64(block).Bytes())
+		forks[hash] = fork
+		return &FilterChange{
+			BlockNumber: hexutil.EncodeUint64(block),
+			BlockHash:   hash,
+			LogIndex:    "0x0",
+			Removed:     removed,
+		}
+	}
+	label := func(c *FilterChange) string {
+		s := c.BlockNumber + forks[c.BlockHash]
+		if c.Removed {
+			s = "-" + s
+		}
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		batches [][]*FilterChange
+		// backfilled khác 0 thì mọi block tới backfilled được đánh dấu đã đọc đủ sau batch đầu tiên, giống Stream.backfill
+		backfilled uint64
+		want       []string
+	}{
+		{
+			name: "overlap is delivered once",
+			batches: [][]*FilterChange{
+				{change(1, "a", false), change(2, "a", false)},
+				{change(2, "a", false), change(3, "a", false)},
+			},
+			backfilled: 2,
+			want:       []string{"0x1a", "0x2a", "0x3a"},
+		},
+		{
+			name: "logs before start are dropped",
+			batches: [][]*FilterChange{
+				{change(0, "a", false), change(0, "a", true), change(1, "a", false)},
+			},
+			want: []string{"0x1a"},
+		},
+		{
+			name: "reorg of delivered logs",
+			batches: [][]*FilterChange{
+				{change(1, "a", false), change(2, "a", false), change(3, "a", false)},
+				{change(2, "a", true), change(3, "a", true), change(2, "b", false)},
+			},
+			want: []string{"0x1a", "0x2a", "0x3a", "-0x2a", "-0x3a", "0x2b"},
+		},
+		{
+			// backfill đã đọc chain mới, subscription gửi lại log bị loại bỏ chưa từng được chuyển đi và log của chain mới
+			name: "reorg before backfill",
+			batches: [][]*FilterChange{
+				{change(1, "a", false), change(2, "b", false)},
+				{change(2, "a", true), change(2, "b", false), change(3, "b", false)},
+			},
+			backfilled: 2,
+			want:       []string{"0x1a", "0x2b", "0x3b"},
+		},
+		{
+			name: "removed log is delivered once",
+			batches: [][]*FilterChange{
+				{change(1, "a", false)},
+				{change(1, "a", true)},
+				{change(1, "a", true)},
+			},
+			want: []string{"0x1a", "-0x1a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := newLogCursor(1)
+			var got []string
+			for i, batch := range tt.batches {
+				delivered, err := cursor.filter(batch)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if i == 0 && tt.backfilled > 0 {
+					cursor.complete(tt.backfilled + 1)
+				}
+				for _, c := range delivered {
+					got = append(got, label(c))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("delivered %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogCursorForgetsOldBlocks(t *testing.T) {
+	cursor := newLogCursor(1)
+	old := &FilterChange{BlockNumber: "0x1", BlockHash: common.HexToHash("0x1"), LogIndex: "0x0"}
+	if delivered, _ := cursor.filter([]*FilterChange{old}); len(delivered) != 1 {
+		t.Fatalf("delivered %d logs, want 1", len(delivered))
+	}
+
+	cursor.complete(2 + logCursorDepth)
+	if len(cursor.delivered) != 0 {
+		t.Fatalf("cursor remembers %d blocks, want 0", len(cursor.delivered))
+	}
+	if delivered, _ := cursor.filter([]*FilterChange{old}); len(delivered) != 0 {
+		t.Fatalf("log older than logCursorDepth delivered again")
+	}
+}
+
+func TestLogCursorForkKeepsOriginal(t *testing.T) {
+	logAt := func(block uint64, index string, removed bool) *FilterChange {
+		return &FilterChange{BlockNumber: hexutil.EncodeUint64(block), BlockHash: common.BigToHash(new(big.Int).SetUint64(block)), LogIndex: index, Removed: removed}
+	}
+	cursor := newLogCursor(1)
+	if delivered, _ := cursor.filter([]*FilterChange{logAt(1, "0x0", false)}); len(delivered) != 1 {
+		t.Fatalf("delivered %d logs, want 1", len(delivered))
+	}
+
+	// bản sao lọc log mới và hoàn tác log đã trả về nhưng cursor gốc không đổi
+	fork := cursor.fork()
+	delivered, _ := fork.filter([]*FilterChange{logAt(1, "0x1", false), logAt(1, "0x0", true), logAt(2, "0x0", false)})
+	if len(delivered) != 3 || fork.next != 2 {
+		t.Fatalf("fork delivered %d logs up to block %d, want 3 logs up to block 2", len(delivered), fork.next)
+	}
+	if cursor.next != 1 || len(cursor.delivered) != 1 || len(cursor.delivered[1]) != 1 {
+		t.Fatalf("original cursor changed: next %d, delivered %v", cursor.next, cursor.delivered)
+	}
+
+	// cursor gốc vẫn trả về các log mà bản sao đã lọc
+	delivered, _ = cursor.filter([]*FilterChange{logAt(1, "0x1", false), logAt(2, "0x0", false)})
+	if len(delivered) != 2 {
+		t.Fatalf("original cursor delivered %d logs, want 2", len(delivered))
+	}
+}
@@ -4,4 +4,7 @@ go 1.16
 
 require (
 	github.com/ethereum/go-ethereum v1.10.13
+	github.com/prometheus/client_golang v1.11.0
+	go.opentelemetry.io/otel v1.2.0
+	go.opentelemetry.io/otel/trace v1.2.0
 )
@@ -0,0 +1,201 @@
+package metrics
+
+import (
+	"context"
+	stderrors "errors"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// các nhóm lỗi của counter lỗi RPC
+const (
+	ErrorClassRevert   = "revert"
+	ErrorClassRPC      = "rpc"
+	ErrorClassHTTP     = "http"
+	ErrorClassTimeout  = "timeout"
+	ErrorClassCanceled = "canceled"
+	ErrorClassNetwork  = "network"
+	ErrorClassOther    = "other"
+)
+
+// Collector là các prometheus collector của thư viện
+// gồm latency và lỗi của từng method RPC, độ trễ của filter và số event đã decode của từng contract
+// dùng Interceptor với eth.WithInterceptors và Collector với eth.WithLogObserver khi tạo client
+type Collector struct {
+	rpcDuration *prometheus.HistogramVec
+	rpcErrors   *prometheus.CounterVec
+	filterLag   *prometheus.GaugeVec
+	events      *prometheus.CounterVec
+
+	mu        sync.RWMutex
+	contracts map[common.Address]registeredContract
+}
+
+// registeredContract là tên và abi dùng để đếm event của một địa chỉ contract
+type registeredContract struct {
+	name string
+	abi  abi.ABI
+}
+
+// NewCollector tạo Collector với tên metric bắt đầu bằng `namespace` và đăng ký vào `registerer`
+func NewCollector(registerer prometheus.Registerer, namespace string) (*Collector, error) {
+	c := &Collector{
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of JSON-RPC calls by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		rpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "errors_total",
+			Help:      "Failed JSON-RPC calls by method and error class.",
+		}, []string{"method", "class"}),
+		filterLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "filter",
+			Name:      "poll_lag_blocks",
+			Help:      "Head block minus last processed block of a filter.",
+		}, []string{"filter"}),
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "events",
+			Name:      "decoded_total",
+			Help:      "Decoded contract events by contract and event name.",
+		}, []string{"contract", "event"}),
+		contracts: make(map[common.Address]registeredContract),
+	}
+
+	if err := registerer.Register(c); err != nil {
+		return nil, errors.Wrap(err, "register metrics collector error")
+	}
+	return c, nil
+}
+
+// Describe thoả mãn prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.rpcDuration.Describe(ch)
+	c.rpcErrors.Describe(ch)
+	c.filterLag.Describe(ch)
+	c.events.Describe(ch)
+}
+
+// Collect thoả mãn prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.rpcDuration.Collect(ch)
+	c.rpcErrors.Collect(ch)
+	c.filterLag.Collect(ch)
+	c.events.Collect(ch)
+}
+
+// Interceptor trả về eth.Interceptor ghi latency và lỗi của mỗi lời gọi JSON-RPC
+// dùng với eth.WithInterceptors khi tạo client
+func (c *Collector) Interceptor() eth.Interceptor {
+	return func(ctx context.Context, call *eth.RPCCall, invoker eth.Invoker) error {
+		start := time.Now()
+		err := invoker(ctx, call)
+		c.rpcDuration.WithLabelValues(call.Method).Observe(time.Since(start).Seconds())
+		if err != nil {
+			c.rpcErrors.WithLabelValues(call.Method, ErrorClass(err)).Inc()
+		}
+		return err
+	}
+}
+
+// ObserveFilterLag thoả mãn eth.LogObserver, ghi độ trễ của filter `filter`: block mới nhất `head` trừ block đã xử lý `processed`
+func (c *Collector) ObserveFilterLag(filter string, head, processed uint64) {
+	var lag float64
+	if head > processed {
+		lag = float64(head - processed)
+	}
+	c.filterLag.WithLabelValues(filter).Set(lag)
+}
+
+// AddContract đăng ký contract `name` tại `address` với abi `contractABI`
+// các log của contract này mà client chuyển qua Watcher, Scanner hoặc Stream được đếm theo tên event
+func (c *Collector) AddContract(name string, address common.Address, contractABI abi.ABI) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.contracts[address] = registeredContract{name: name, abi: contractABI}
+}
+
+// ObserveLogs thoả mãn eth.LogObserver, đếm event của các contract đã đăng ký bằng AddContract
+// log bị loại bỏ do reorg không được đếm
+func (c *Collector) ObserveLogs(changes []*eth.FilterChange) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, change := range changes {
+		registered, ok := c.contracts[change.Address]
+		if !ok || change.Removed {
+			continue
+		}
+		c.ObserveEvents(registered.name, registered.abi, []*eth.FilterChange{change})
+	}
+}
+
+// ObserveEvents đếm các event của contract `contract` trong `changes` theo tên event trong `contractABI`
+// các log không phải event của `contractABI` bị bỏ qua
+func (c *Collector) ObserveEvents(contract string, contractABI abi.ABI, changes []*eth.FilterChange) {
+	for _, change := range changes {
+		if len(change.Topics) == 0 {
+			continue
+		}
+		event, err := contractABI.EventByID(change.EventID())
+		if err != nil {
+			continue
+		}
+		c.events.WithLabelValues(contract, event.Name).Inc()
+	}
+}
+
+// ErrorClass trả về nhóm của lỗi RPC `err`, dùng làm nhãn `class` của counter lỗi
+func ErrorClass(err error) string {
+	var revertErr *eth.RevertError
+	if stderrors.As(err, &revertErr) {
+		return ErrorClassRevert
+	}
+
+	var httpErr rpc.HTTPError
+	if stderrors.As(err, &httpErr) {
+		return ErrorClassHTTP
+	}
+
+	var rpcErr rpc.Error
+	if stderrors.As(err, &rpcErr) {
+		// mã lỗi 3 là lỗi revert theo quy ước của geth
+		if rpcErr.ErrorCode() == 3 || strings.HasPrefix(rpcErr.Error(), "execution reverted") {
+			return ErrorClassRevert
+		}
+		return ErrorClassRPC
+	}
+
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassTimeout
+	}
+	if stderrors.Is(err, context.Canceled) {
+		return ErrorClassCanceled
+	}
+
+	var netErr net.Error
+	if stderrors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrorClassTimeout
+		}
+		return ErrorClassNetwork
+	}
+	return ErrorClassOther
+}
+
+var _ eth.LogObserver = (*Collector)(nil)
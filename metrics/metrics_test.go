@@ -0,0 +1,249 @@
+package metrics_test
+
+import (
+	"context"
+	stderrors "errors"
+	"github.com/adene-develop/adene-goeth/eth"
+	"github.com/adene-develop/adene-goeth/eth/ethtest"
+	"github.com/adene-develop/adene-goeth/metrics"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const tokenABIJSON = `[
+	{"type":"event","name":"Transfer","anonymous":false,"inputs":[
+		{"name":"from","type":"address","indexed":true},
+		{"name":"to","type":"address","indexed":true},
+		{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Approval","anonymous":false,"inputs":[
+		{"name":"owner","type":"address","indexed":true},
+		{"name":"spender","type":"address","indexed":true},
+		{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+var (
+	tokenABI     = mustParseABI(tokenABIJSON)
+	tokenAddress = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	otherAddress = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+)
+
+func mustParseABI(json string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(json))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// timeoutError là net.Error hết thời gian chờ
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"revert error", errors.Wrap(&eth.RevertError{Reason: "paused"}, "call contract error"), metrics.ErrorClassRevert},
+		{"revert code", &eth.RPCError{Code: 3, Message: "execution reverted: paused"}, metrics.ErrorClassRevert},
+		{"revert message", &eth.RPCError{Code: -32000, Message: "execution reverted"}, metrics.ErrorClassRevert},
+		{"http", errors.Wrap(rpc.HTTPError{StatusCode: http.StatusBadGateway}, "call rpc error"), metrics.ErrorClassHTTP},
+		{"rpc", &eth.RPCError{Code: -32000, Message: "header not found"}, metrics.ErrorClassRPC},
+		{"timeout", errors.Wrap(context.DeadlineExceeded, "call rpc error"), metrics.ErrorClassTimeout},
+		{"canceled", context.Canceled, metrics.ErrorClassCanceled},
+		{"network timeout", &net.OpError{Op: "read", Err: timeoutError{}}, metrics.ErrorClassTimeout},
+		{"network", &net.OpError{Op: "dial", Err: stderrors.New("connection refused")}, metrics.ErrorClassNetwork},
+		{"other", stderrors.New("decode error"), metrics.ErrorClassOther},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := metrics.ErrorClass(test.err); got != test.want {
+				t.Fatalf("ErrorClass(%v) = %q, want %q", test.err, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewCollectorRegisterError(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := metrics.NewCollector(registry, "adene"); err != nil {
+		t.Fatalf("NewCollector: %v", err)
+	}
+	// metric trùng tên không đăng ký được lần thứ hai
+	if _, err := metrics.NewCollector(registry, "adene"); err == nil {
+		t.Fatal("NewCollector registered the same metrics twice")
+	}
+}
+
+func TestCollectorInterceptor(t *testing.T) {
+	tests := []struct {
+		name      string
+		fault     *ethtest.Fault
+		wantClass string
+	}{
+		{name: "success"},
+		{name: "rpc error", fault: &ethtest.Fault{Error: &eth.RPCError{Code: ethtest.CodeServerError, Message: "header not found"}}, wantClass: metrics.ErrorClassRPC},
+		{name: "http error", fault: &ethtest.Fault{HTTPStatus: http.StatusBadRequest}, wantClass: metrics.ErrorClassHTTP},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			collector, err := metrics.NewCollector(registry, "adene")
+			if err != nil {
+				t.Fatal(err)
+			}
+			client, node := ethtest.NewClient(t, eth.WithInterceptors(collector.Interceptor()))
+			if test.fault != nil {
+				node.Inject("eth_blockNumber", *test.fault)
+			}
+
+			var head hexutil.Uint64
+			err = client.RpcClient().CallContext(ethtest.Context(t), &head, "eth_blockNumber")
+			if (err != nil) != (test.wantClass != "") {
+				t.Fatalf("eth_blockNumber error = %v, want error %v", err, test.wantClass != "")
+			}
+
+			if got := histogramCount(t, registry, "adene_rpc_request_duration_seconds"); got != 1 {
+				t.Fatalf("latency samples = %d, want 1", got)
+			}
+			wantErrors := ""
+			if test.wantClass != "" {
+				wantErrors = `
+# HELP adene_rpc_errors_total Failed JSON-RPC calls by method and error class.
+# TYPE adene_rpc_errors_total counter
+adene_rpc_errors_total{class="` + test.wantClass + `",method="eth_blockNumber"} 1
+`
+			}
+			if err = testutil.GatherAndCompare(registry, strings.NewReader(wantErrors), "adene_rpc_errors_total"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// histogramCount trả về tổng số mẫu của histogram `name` trong `registry`
+func histogramCount(t *testing.T, registry *prometheus.Registry, name string) uint64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count uint64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			count += metric.GetHistogram().GetSampleCount()
+		}
+	}
+	return count
+}
+
+func TestCollectorObserveFilterLag(t *testing.T) {
+	tests := []struct {
+		name      string
+		head      uint64
+		processed uint64
+		want      float64
+	}{
+		{name: "behind", head: 120, processed: 100, want: 20},
+		{name: "caught up", head: 100, processed: 100, want: 0},
+		{name: "ahead of head", head: 100, processed: 101, want: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collector, err := metrics.NewCollector(prometheus.NewRegistry(), "adene")
+			if err != nil {
+				t.Fatal(err)
+			}
+			collector.ObserveFilterLag("transfers", test.head, test.processed)
+
+			want := `
+# HELP adene_filter_poll_lag_blocks Head block minus last processed block of a filter.
+# TYPE adene_filter_poll_lag_blocks gauge
+adene_filter_poll_lag_blocks{filter="transfers"} ` + strconv.FormatFloat(test.want, 'g', -1, 64) + `
+`
+			if err = testutil.CollectAndCompare(collector, strings.NewReader(want), "adene_filter_poll_lag_blocks"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// transferLog trả về log Transfer của contract `address`
+func transferLog(address common.Address, removed bool) *eth.FilterChange {
+	return &eth.FilterChange{
+		Address: address,
+		Topics:  []common.Hash{tokenABI.Events["Transfer"].ID, {}, {}},
+		Data:    make([]byte, 32),
+		Removed: removed,
+	}
+}
+
+func TestCollectorObserveLogs(t *testing.T) {
+	approval := transferLog(tokenAddress, false)
+	approval.Topics[0] = tokenABI.Events["Approval"].ID
+	unknown := transferLog(tokenAddress, false)
+	unknown.Topics[0] = crypto.Keccak256Hash([]byte("Unknown()"))
+	anonymous := &eth.FilterChange{Address: tokenAddress}
+
+	tests := []struct {
+		name    string
+		changes []*eth.FilterChange
+		// want là số event đếm được theo tên event
+		want map[string]float64
+	}{
+		{name: "registered contract", changes: []*eth.FilterChange{transferLog(tokenAddress, false), transferLog(tokenAddress, false), approval}, want: map[string]float64{"Transfer": 2, "Approval": 1}},
+		{name: "unregistered contract", changes: []*eth.FilterChange{transferLog(otherAddress, false)}},
+		{name: "removed log", changes: []*eth.FilterChange{transferLog(tokenAddress, true)}},
+		{name: "unknown event", changes: []*eth.FilterChange{unknown}},
+		{name: "log without topics", changes: []*eth.FilterChange{anonymous, transferLog(tokenAddress, false)}, want: map[string]float64{"Transfer": 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collector, err := metrics.NewCollector(prometheus.NewRegistry(), "adene")
+			if err != nil {
+				t.Fatal(err)
+			}
+			collector.AddContract("token", tokenAddress, tokenABI)
+			collector.ObserveLogs(test.changes)
+
+			want := ""
+			if len(test.want) > 0 {
+				want = `
+# HELP adene_events_decoded_total Decoded contract events by contract and event name.
+# TYPE adene_events_decoded_total counter
+`
+				for _, event := range []string{"Approval", "Transfer"} {
+					if count, ok := test.want[event]; ok {
+						want += `adene_events_decoded_total{contract="token",event="` + event + `"} ` + strconv.FormatFloat(count, 'g', -1, 64) + "\n"
+					}
+				}
+			}
+			if err = testutil.CollectAndCompare(collector, strings.NewReader(want), "adene_events_decoded_total"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}